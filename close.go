@@ -0,0 +1,67 @@
+package zap
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+const (
+	// Default close timeout.
+	DefaultCloseTimeout = 5 * time.Second
+)
+
+// Syncer interface, flush buffered data.
+type Syncer interface {
+	Sync() error
+}
+
+// Close syncs and closes all writes with default timeout,
+// the logger should be synced before close.
+func (c *Config) Close() error {
+	return c.CloseTimeout(DefaultCloseTimeout)
+}
+
+// Close syncs and closes all writes in order, writes implement Syncer are synced
+// and writes implement io.Closer are closed, return error when timeout.
+func (c *Config) CloseTimeout(timeout time.Duration) error {
+	done := make(chan error, 1)
+
+	go func() {
+		var errs []error
+
+		for i, write := range c.Writes {
+			if write == nil {
+				continue
+			}
+
+			writer := write.GetWriter()
+
+			if syncer, ok := writer.(Syncer); ok {
+				if err := syncer.Sync(); err != nil {
+					errs = append(errs, fmt.Errorf("writes[%d]: sync %s: %v", i, write.Name, err))
+				}
+			}
+
+			if closer, ok := writer.(io.Closer); ok {
+				if err := closer.Close(); err != nil {
+					errs = append(errs, fmt.Errorf("writes[%d]: close %s: %v", i, write.Name, err))
+				}
+			}
+		}
+
+		done <- multierr.Combine(errs...)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("close writes timeout after %s", timeout)
+	}
+}
@@ -0,0 +1,65 @@
+package zap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+type closeWriter struct {
+	synced, closed bool
+	block          chan struct{}
+	order          *[]string
+	name           string
+}
+
+func (w *closeWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *closeWriter) Sync() error {
+	w.synced = true
+	return nil
+}
+
+func (w *closeWriter) Close() error {
+	if w.block != nil {
+		<-w.block
+	}
+	w.closed = true
+	*w.order = append(*w.order, w.name)
+	return errors.New("closed " + w.name)
+}
+
+func TestConfig_Close(t *testing.T) {
+	var order []string
+
+	a := &closeWriter{name: "a", order: &order}
+	b := &closeWriter{name: "b", order: &order}
+
+	config := GetDefaultConfig().
+		AddSyncerWrite(&syncer.Write{Name: "a", Config: a}).
+		AddSyncerWrite(&syncer.Write{Name: "b", Config: b})
+
+	err := config.Close()
+	if !a.synced || !a.closed || !b.synced || !b.closed {
+		t.Fatal("writes should be synced and closed")
+	}
+	if strings.Join(order, ",") != "a,b" {
+		t.Fatalf("writes should be closed in order: %v", order)
+	}
+	if err == nil || !strings.Contains(err.Error(), "writes[1]: close b") {
+		t.Fatalf("close errors should be returned: %v", err)
+	}
+
+	c := &closeWriter{name: "c", order: &order, block: make(chan struct{})}
+	defer close(c.block)
+
+	config = GetDefaultConfig().AddSyncerWrite(&syncer.Write{Name: "c", Config: c})
+	if err := config.CloseTimeout(10 * time.Millisecond); err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("close should be timeout: %v", err)
+	}
+}
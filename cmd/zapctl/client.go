@@ -0,0 +1,455 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	zapConfig "github.com/go-framework/zap"
+)
+
+// Logger level.
+type loggerLevel struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string `json:"name"`
+	// Logger level.
+	Level zapcore.Level `json:"level"`
+	// Override expire time, zero is never expire.
+	ExpireAt time.Time `json:"expire_at"`
+}
+
+// Level change.
+type levelChange struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string
+	// Effective level before change.
+	Previous zapcore.Level
+	// Effective level after change.
+	Level zapcore.Level
+	// Override expire time, zero is never expire.
+	ExpireAt time.Time
+}
+
+// Writer state.
+type writerState struct {
+	// Write index.
+	Index int `json:"index"`
+	// Write name.
+	Name string `json:"name"`
+	// Write minimum enabled level.
+	Level *zapcore.Level `json:"level"`
+	// Write maximum enabled level.
+	MaxLevel *zapcore.Level `json:"max_level"`
+	// Write encoding.
+	Encoding string `json:"encoding"`
+	// Write is healthy.
+	Healthy bool `json:"healthy"`
+	// Health error.
+	Error string `json:"error"`
+}
+
+// Admin client.
+type client interface {
+	// Get effective level of logger name.
+	GetLevel(ctx context.Context, name string) (*loggerLevel, error)
+	// Set logger level by name, the level is reverted after ttl when ttl is positive.
+	SetLevel(ctx context.Context, name string, level zapcore.Level, ttl time.Duration) (*levelChange, error)
+	// Unset named logger level.
+	UnsetLevel(ctx context.Context, name string) (*levelChange, error)
+	// List the root logger and named loggers levels.
+	ListLoggers(ctx context.Context) ([]*loggerLevel, error)
+	// List writers with their health.
+	ListWriters(ctx context.Context) ([]*writerState, error)
+	// Tail the live log entries, fn is called with every received entry.
+	Tail(ctx context.Context, req *zapConfig.TailRequest, fn func(*zapConfig.LogEntry) error) error
+	// Close client.
+	Close() error
+}
+
+// Client options.
+type options struct {
+	// gRPC admin address.
+	Addr string
+	// HTTP admin url, the HTTP client is used when it is not empty.
+	HTTP string
+	// Enable TLS.
+	TLS bool
+	// CA certificate file.
+	CA string
+	// Client certificate file.
+	Cert string
+	// Client key file.
+	Key string
+	// Skip server certificate verification.
+	Insecure bool
+	// Request timeout.
+	Timeout time.Duration
+}
+
+// get tls config.
+func (o *options) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{InsecureSkipVerify: o.Insecure}
+
+	if o.CA != "" {
+		data, err := ioutil.ReadFile(o.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca: no certificate found in %s", o.CA)
+		}
+		config.RootCAs = pool
+	}
+
+	if o.Cert != "" || o.Key != "" {
+		cert, err := tls.LoadX509KeyPair(o.Cert, o.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// new client by options.
+func newClient(o *options) (client, error) {
+	if o.HTTP != "" {
+		return newHTTPClient(o)
+	}
+	return newGRPCClient(o)
+}
+
+// gRPC client.
+type grpcClient struct {
+	conn   *grpc.ClientConn
+	client zapConfig.LevelServiceV2Client
+	log    zapConfig.LogServiceClient
+}
+
+// new gRPC client.
+func newGRPCClient(o *options) (*grpcClient, error) {
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if o.TLS {
+		config, err := o.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}
+	}
+
+	conn, err := grpc.Dial(o.Addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcClient{
+		conn:   conn,
+		client: zapConfig.NewLevelServiceV2Client(conn),
+		log:    zapConfig.NewLogServiceClient(conn),
+	}, nil
+}
+
+// convert proto logger level.
+func fromProtoLoggerLevel(l *zapConfig.LoggerLevel) (*loggerLevel, error) {
+	level, err := zapConfig.LevelToZap(l.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &loggerLevel{Name: l.Name, Level: level, ExpireAt: unixTime(l.ExpireAt)}, nil
+}
+
+// convert proto writer state, unset levels are nil.
+func fromProtoWriterState(w *zapConfig.WriterState) (*writerState, error) {
+	state := &writerState{
+		Index:    int(w.Index),
+		Name:     w.Name,
+		Encoding: w.Encoding,
+		Healthy:  w.Healthy,
+		Error:    w.Error,
+	}
+	if w.HasLevel {
+		level, err := zapConfig.LevelToZap(w.Level)
+		if err != nil {
+			return nil, err
+		}
+		state.Level = &level
+	}
+	if w.HasMaxLevel {
+		level, err := zapConfig.LevelToZap(w.MaxLevel)
+		if err != nil {
+			return nil, err
+		}
+		state.MaxLevel = &level
+	}
+	return state, nil
+}
+
+// convert unix seconds to time, zero is zero time.
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// convert proto level change.
+func fromProtoLevelChange(c *zapConfig.LevelChange) (*levelChange, error) {
+	previous, err := zapConfig.LevelToZap(c.Previous)
+	if err != nil {
+		return nil, err
+	}
+	level, err := zapConfig.LevelToZap(c.Level)
+	if err != nil {
+		return nil, err
+	}
+	return &levelChange{Name: c.Name, Previous: previous, Level: level, ExpireAt: unixTime(c.ExpireAt)}, nil
+}
+
+func (c *grpcClient) GetLevel(ctx context.Context, name string) (*loggerLevel, error) {
+	l, err := c.client.GetLevel(ctx, &zapConfig.LoggerName{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoLoggerLevel(l)
+}
+
+func (c *grpcClient) SetLevel(ctx context.Context, name string, level zapcore.Level, ttl time.Duration) (*levelChange, error) {
+	l, err := zapConfig.LevelFromZap(level)
+	if err != nil {
+		return nil, err
+	}
+
+	change, err := c.client.SetLevel(ctx, &zapConfig.SetLoggerLevelRequest{
+		Name:       name,
+		Level:      l,
+		TtlSeconds: int64((ttl + time.Second - 1) / time.Second),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromProtoLevelChange(change)
+}
+
+func (c *grpcClient) UnsetLevel(ctx context.Context, name string) (*levelChange, error) {
+	change, err := c.client.UnsetLevel(ctx, &zapConfig.LoggerName{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtoLevelChange(change)
+}
+
+func (c *grpcClient) ListLoggers(ctx context.Context) ([]*loggerLevel, error) {
+	loggers, err := c.client.ListLoggers(ctx, &zapConfig.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]*loggerLevel, 0, len(loggers.Loggers))
+	for _, logger := range loggers.Loggers {
+		level, err := fromProtoLoggerLevel(logger)
+		if err != nil {
+			return nil, err
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
+
+func (c *grpcClient) ListWriters(ctx context.Context) ([]*writerState, error) {
+	writers, err := c.client.ListWriters(ctx, &zapConfig.Empty{})
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*writerState, 0, len(writers.Writers))
+	for _, writer := range writers.Writers {
+		state, err := fromProtoWriterState(writer)
+		if err != nil {
+			return nil, err
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (c *grpcClient) Tail(ctx context.Context, req *zapConfig.TailRequest, fn func(*zapConfig.LogEntry) error) error {
+	stream, err := c.log.Tail(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+}
+
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
+
+// HTTP client.
+type httpClient struct {
+	url    string
+	client *http.Client
+}
+
+// new HTTP client.
+func newHTTPClient(o *options) (*httpClient, error) {
+	u, err := url.Parse(o.HTTP)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if o.TLS || u.Scheme == "https" {
+		if transport.TLSClientConfig, err = o.tlsConfig(); err != nil {
+			return nil, err
+		}
+		if u.Scheme == "http" {
+			u.Scheme = "https"
+		}
+	}
+
+	return &httpClient{
+		url:    strings.TrimSuffix(u.String(), "/"),
+		client: &http.Client{Transport: transport},
+	}, nil
+}
+
+// do HTTP request and decode json response.
+func (c *httpClient) do(ctx context.Context, method, path string, body, v interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := jsoniter.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if jsoniter.Unmarshal(data, &e) == nil && e.Error != "" {
+			return fmt.Errorf("%s %s: %s", method, path, e.Error)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+
+	return jsoniter.Unmarshal(data, v)
+}
+
+func (c *httpClient) GetLevel(ctx context.Context, name string) (*loggerLevel, error) {
+	level := &loggerLevel{}
+	if err := c.do(ctx, http.MethodGet, "/loggers/"+url.PathEscape(name), nil, level); err != nil {
+		return nil, err
+	}
+	return level, nil
+}
+
+func (c *httpClient) SetLevel(ctx context.Context, name string, level zapcore.Level, ttl time.Duration) (*levelChange, error) {
+	previous, err := c.GetLevel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]interface{}{"level": level}
+	if ttl > 0 {
+		req["ttl"] = ttl.String()
+	}
+
+	current := &loggerLevel{}
+	if err := c.do(ctx, http.MethodPut, "/loggers/"+url.PathEscape(name), req, current); err != nil {
+		return nil, err
+	}
+
+	return &levelChange{Name: name, Previous: previous.Level, Level: current.Level, ExpireAt: current.ExpireAt}, nil
+}
+
+func (c *httpClient) UnsetLevel(ctx context.Context, name string) (*levelChange, error) {
+	previous, err := c.GetLevel(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	current := &loggerLevel{}
+	if err := c.do(ctx, http.MethodDelete, "/loggers/"+url.PathEscape(name), nil, current); err != nil {
+		return nil, err
+	}
+
+	return &levelChange{Name: name, Previous: previous.Level, Level: current.Level}, nil
+}
+
+func (c *httpClient) ListLoggers(ctx context.Context) ([]*loggerLevel, error) {
+	var levels []*loggerLevel
+	if err := c.do(ctx, http.MethodGet, "/loggers", nil, &levels); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+func (c *httpClient) ListWriters(ctx context.Context) ([]*writerState, error) {
+	var writers []*writerState
+	if err := c.do(ctx, http.MethodGet, "/writers", nil, &writers); err != nil {
+		return nil, err
+	}
+	return writers, nil
+}
+
+func (c *httpClient) Tail(ctx context.Context, req *zapConfig.TailRequest, fn func(*zapConfig.LogEntry) error) error {
+	return errors.New("tail is only supported by the gRPC admin endpoint, unset -http")
+}
+
+func (c *httpClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
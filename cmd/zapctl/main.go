@@ -0,0 +1,354 @@
+// Zapctl manages logger levels of remote processes through the gRPC or HTTP admin endpoints.
+//
+//	zapctl [flags] get-level [-logger name]
+//	zapctl [flags] set-level <level> [-logger name] [-ttl 10m]
+//	zapctl [flags] unset-level -logger name
+//	zapctl [flags] list-loggers
+//	zapctl [flags] list-writers
+//	zapctl [flags] tail [-level warn] [-logger name] [-field key=value]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap/zapcore"
+
+	zapConfig "github.com/go-framework/zap"
+)
+
+// Command.
+type command struct {
+	// Command name.
+	name string
+	// Command usage.
+	usage string
+	// Run command.
+	run func(ctx context.Context, c client, args []string, out io.Writer) error
+}
+
+// Commands.
+var commands = []*command{
+	{"get-level", "get-level [-logger name]", getLevel},
+	{"set-level", "set-level <level> [-logger name] [-ttl 10m]", setLevel},
+	{"unset-level", "unset-level -logger name", unsetLevel},
+	{"list-loggers", "list-loggers", listLoggers},
+	{"list-writers", "list-writers", listWriters},
+	{"tail", "tail [-level warn] [-logger name] [-field key=value]", tail},
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "zapctl:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run zapctl with args.
+func run(args []string, out, errOut io.Writer) error {
+	o := &options{}
+
+	fs := flag.NewFlagSet("zapctl", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	fs.StringVar(&o.Addr, "addr", "localhost:9090", "gRPC admin address")
+	fs.StringVar(&o.HTTP, "http", "", "HTTP admin url, e.g. http://localhost:8080/debug/log, used instead of gRPC when set")
+	fs.BoolVar(&o.TLS, "tls", false, "enable TLS")
+	fs.StringVar(&o.CA, "ca", "", "CA certificate file")
+	fs.StringVar(&o.Cert, "cert", "", "client certificate file")
+	fs.StringVar(&o.Key, "key", "", "client key file")
+	fs.BoolVar(&o.Insecure, "insecure", false, "skip server certificate verification")
+	fs.DurationVar(&o.Timeout, "timeout", 10*time.Second, "request timeout")
+	fs.Usage = func() {
+		fmt.Fprintln(errOut, "usage: zapctl [flags] <command> [args]")
+		fmt.Fprintln(errOut, "commands:")
+		for _, cmd := range commands {
+			fmt.Fprintln(errOut, "  "+cmd.usage)
+		}
+		fmt.Fprintln(errOut, "flags:")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("command is required")
+	}
+
+	var cmd *command
+	for _, c := range commands {
+		if c.name == fs.Arg(0) {
+			cmd = c
+		}
+	}
+	if cmd == nil {
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", fs.Arg(0))
+	}
+
+	c, err := newClient(o)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	if cmd.name != "tail" && o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
+
+	return cmd.run(ctx, c, fs.Args()[1:], out)
+}
+
+// parse command flags, flags may follow the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// format expire time.
+func expire(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+// format logger name.
+func loggerName(name string) string {
+	if name == "" {
+		return "<root>"
+	}
+	return name
+}
+
+// print level change.
+func printChange(out io.Writer, change *levelChange) {
+	fmt.Fprintf(out, "%s: %s -> %s", loggerName(change.Name), change.Previous, change.Level)
+	if !change.ExpireAt.IsZero() {
+		fmt.Fprintf(out, " until %s", expire(change.ExpireAt))
+	}
+	fmt.Fprintln(out)
+}
+
+// get level command.
+func getLevel(ctx context.Context, c client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("get-level", flag.ContinueOnError)
+	name := fs.String("logger", "", "logger name, empty is the root logger")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	level, err := c.GetLevel(ctx, *name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, level.Level)
+
+	return nil
+}
+
+// set level command.
+func setLevel(ctx context.Context, c client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("set-level", flag.ContinueOnError)
+	name := fs.String("logger", "", "logger name, empty is the root logger")
+	ttl := fs.Duration("ttl", 0, "override time to live, the level is reverted when expired, zero is permanent")
+	positional, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errors.New("usage: set-level <level> [-logger name] [-ttl 10m]")
+	}
+	if *ttl < 0 {
+		return fmt.Errorf("ttl: must not be negative")
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(positional[0])); err != nil {
+		return err
+	}
+
+	change, err := c.SetLevel(ctx, *name, level, *ttl)
+	if err != nil {
+		return err
+	}
+
+	printChange(out, change)
+
+	return nil
+}
+
+// unset level command.
+func unsetLevel(ctx context.Context, c client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("unset-level", flag.ContinueOnError)
+	name := fs.String("logger", "", "logger name")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("logger: must not be empty")
+	}
+
+	change, err := c.UnsetLevel(ctx, *name)
+	if err != nil {
+		return err
+	}
+
+	printChange(out, change)
+
+	return nil
+}
+
+// list loggers command.
+func listLoggers(ctx context.Context, c client, args []string, out io.Writer) error {
+	loggers, err := c.ListLoggers(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LOGGER\tLEVEL\tEXPIRE")
+	for _, logger := range loggers {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", loggerName(logger.Name), logger.Level, expire(logger.ExpireAt))
+	}
+
+	return w.Flush()
+}
+
+// list writers command.
+func listWriters(ctx context.Context, c client, args []string, out io.Writer) error {
+	writers, err := c.ListWriters(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INDEX\tNAME\tLEVEL\tMAX LEVEL\tENCODING\tHEALTH")
+	for _, writer := range writers {
+		level, maxLevel, encoding := "-", "-", "-"
+		if writer.Level != nil {
+			level = writer.Level.String()
+		}
+		if writer.MaxLevel != nil {
+			maxLevel = writer.MaxLevel.String()
+		}
+		if writer.Encoding != "" {
+			encoding = writer.Encoding
+		}
+		health := "ok"
+		if !writer.Healthy {
+			health = writer.Error
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", writer.Index, writer.Name, level, maxLevel, encoding, health)
+	}
+
+	return w.Flush()
+}
+
+// Repeated key=value flag.
+type fieldsFlag map[string]string
+
+// Implement flag Value interface.
+func (f fieldsFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+// Implement flag Value interface.
+func (f fieldsFlag) Set(value string) error {
+	n := strings.IndexByte(value, '=')
+	if n <= 0 {
+		return fmt.Errorf("field %q should be key=value", value)
+	}
+	f[value[:n]] = value[n+1:]
+	return nil
+}
+
+// tail command.
+func tail(ctx context.Context, c client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	levelText := fs.String("level", "debug", "minimum entry level")
+	name := fs.String("logger", "", "logger name prefix, empty matches all loggers")
+	fields := fieldsFlag{}
+	fs.Var(fields, "field", "field should be equal as key=value, can be repeated")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(*levelText)); err != nil {
+		return err
+	}
+	minLevel, err := zapConfig.LevelFromZap(level)
+	if err != nil {
+		return err
+	}
+
+	req := &zapConfig.TailRequest{MinLevel: minLevel, Logger: *name, Fields: fields}
+
+	return c.Tail(ctx, req, func(entry *zapConfig.LogEntry) error {
+		level, err := zapConfig.LevelToZap(entry.Level)
+		if err != nil {
+			return err
+		}
+
+		line := []string{
+			time.Unix(0, entry.Time).Format(time.RFC3339Nano),
+			level.CapitalString(),
+		}
+		if entry.Logger != "" {
+			line = append(line, entry.Logger)
+		}
+		if entry.Caller != "" {
+			line = append(line, entry.Caller)
+		}
+		line = append(line, entry.Message)
+		if len(entry.Fields) > 0 {
+			data, err := jsoniter.ConfigCompatibleWithStandardLibrary.Marshal(entry.Fields)
+			if err != nil {
+				return err
+			}
+			line = append(line, string(data))
+		}
+
+		if _, err := fmt.Fprintln(out, strings.Join(line, "\t")); err != nil {
+			return err
+		}
+		if entry.Stack != "" {
+			if _, err := fmt.Fprintln(out, entry.Stack); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	zapConfig "github.com/go-framework/zap"
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestRun(t *testing.T) {
+	config := &zapConfig.Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: lumberjack.Name, Config: lumberjack.New(t.TempDir() + "/app.log")})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	zapConfig.RegisterLevelServiceV2Server(server, zapConfig.NewLevelServerV2(config))
+	zapConfig.RegisterLogServiceServer(server, config)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	httpServer := httptest.NewServer(config.Handler())
+	defer httpServer.Close()
+
+	grpcAddr := "-addr=" + listener.Addr().String()
+	httpAddr := "-http=" + httpServer.URL
+
+	tests := []struct {
+		args   []string
+		expect string
+		err    string
+	}{
+		{[]string{grpcAddr, "get-level"}, "info\n", ""},
+		{[]string{grpcAddr, "set-level", "debug", "--logger", "db", "--ttl", "10m"}, "db: info -> debug until ", ""},
+		{[]string{grpcAddr, "set-level", "-logger=http", "warn"}, "http: info -> warn\n", ""},
+		{[]string{grpcAddr, "set-level", "verbose"}, "", "unrecognized level"},
+		{[]string{grpcAddr, "get-level", "-logger", "db.sql"}, "debug\n", ""},
+		{[]string{grpcAddr, "list-loggers"}, "<root>  info   -\ndb      debug  ", ""},
+		{[]string{grpcAddr, "list-writers"}, "0      lumberjack  -      -          -         ok\n", ""},
+		{[]string{httpAddr, "unset-level", "-logger", "db"}, "db: debug -> info\n", ""},
+		{[]string{httpAddr, "set-level", "error", "-ttl", "1m"}, "<root>: info -> error until ", ""},
+		{[]string{httpAddr, "get-level", "-logger", "db"}, "error\n", ""},
+		{[]string{httpAddr, "list-writers"}, "0      lumberjack  -      -          -         ok\n", ""},
+		{[]string{httpAddr, "unset-level"}, "", "logger: must not be empty"},
+		{[]string{grpcAddr, "unknown"}, "", "unknown command"},
+		{[]string{httpAddr, "tail"}, "", "only supported by the gRPC"},
+		{[]string{grpcAddr, "tail", "-field", "user"}, "", "should be key=value"},
+	}
+
+	for _, test := range tests {
+		out := &bytes.Buffer{}
+		err := run(test.args, out, &bytes.Buffer{})
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("%v expect error %q, got %v", test.args, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", test.args, err)
+			continue
+		}
+		if !strings.Contains(out.String(), test.expect) {
+			t.Errorf("%v expect %q, got %q", test.args, test.expect, out.String())
+		}
+	}
+}
+
+// Synchronized buffer.
+type syncBuffer struct {
+	bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Buffer.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.Buffer.String()
+}
+
+func TestTail(t *testing.T) {
+	config := &zapConfig.Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	logger := config.NewZapLogger()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	zapConfig.RegisterLogServiceServer(server, config)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	c, err := newGRPCClient(&options{Addr: listener.Addr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out := &syncBuffer{}
+	done := make(chan error, 1)
+	go func() {
+		done <- tail(ctx, c, []string{"-level", "warn", "-logger", "db", "-field", "user=alice"}, out)
+	}()
+
+	// log until the tail is subscribed.
+	for i := 0; !strings.Contains(out.String(), "matched"); i++ {
+		if i > 100 {
+			t.Fatalf("tail output %q", out.String())
+		}
+		logger.Named("db").Info("filtered", zap.String("user", "alice"))
+		logger.Named("db").Warn("matched", zap.String("user", "alice"))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; status.Code(err) != codes.Canceled {
+		t.Errorf("expect canceled, got %v", err)
+	}
+
+	line := strings.SplitN(out.String(), "\n", 2)[0]
+	if !strings.Contains(line, "\tWARN\tdb\t") || !strings.HasSuffix(line, "\tmatched\t{\"user\":\"alice\"}") {
+		t.Errorf("unexpected tail line %q", line)
+	}
+	if strings.Contains(out.String(), "filtered") {
+		t.Errorf("unexpected filtered entry %q", out.String())
+	}
+}
@@ -0,0 +1,44 @@
+// Zapschema prints the JSON Schema of the logger config with the registered writers,
+// editors validate config.yaml with it, e.g. with the yaml-language-server modeline:
+//
+//	# yaml-language-server: $schema=./zap.schema.json
+//
+// Usage:
+//
+//	zapschema [-o zap.schema.json]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	zapConfig "github.com/go-framework/zap"
+)
+
+func main() {
+	output := flag.String("o", "", "output file, default is stdout")
+	flag.Parse()
+
+	if err := run(*output); err != nil {
+		fmt.Fprintln(os.Stderr, "zapschema:", err)
+		os.Exit(1)
+	}
+}
+
+// run zapschema, the schema is written into output file or stdout.
+func run(output string) error {
+	data, err := zapConfig.MarshalSchema()
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	if output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return ioutil.WriteFile(output, data, 0644)
+}
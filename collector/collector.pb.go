@@ -0,0 +1,357 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: collector/collector.proto
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+	"math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Logger level enum.
+// Level values are not equal to zap logger level values, use LevelToZap and LevelFromZap to convert.
+type Level int32
+
+const (
+	// DebugLevel logs are typically voluminous, and are usually disabled in
+	// production.
+	Level_Debug Level = 0
+	// InfoLevel is the default logging priority.
+	Level_Info Level = 1
+	// WarnLevel logs are more important than Info, but don't need individual
+	// human review.
+	Level_Warn Level = 2
+	// ErrorLevel logs are high-priority. If an application is running smoothly,
+	// it shouldn't generate any error-level logs.
+	Level_Error Level = 3
+	// DPanicLevel logs are particularly important errors. In development the
+	// logger panics after writing the message.
+	Level_DPanic Level = 4
+	// PanicLevel logs a message, then panics.
+	Level_Panic Level = 5
+	// FatalLevel logs a message, then calls os.Exit(1).
+	Level_Fatal Level = 6
+)
+
+var Level_name = map[int32]string{
+	0: "Debug",
+	1: "Info",
+	2: "Warn",
+	3: "Error",
+	4: "DPanic",
+	5: "Panic",
+	6: "Fatal",
+}
+
+var Level_value = map[string]int32{
+	"Debug":  0,
+	"Info":   1,
+	"Warn":   2,
+	"Error":  3,
+	"DPanic": 4,
+	"Panic":  5,
+	"Fatal":  6,
+}
+
+func (x Level) String() string {
+	return proto.EnumName(Level_name, int32(x))
+}
+
+func (Level) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_515c2b830bcedeab, []int{0}
+}
+
+// Log entry message.
+type LogEntry struct {
+	// Entry time as unix nanoseconds.
+	Time int64 `protobuf:"varint,1,opt,name=time,proto3" json:"time,omitempty"`
+	// Entry level.
+	Level Level `protobuf:"varint,2,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	// Logger name.
+	Logger string `protobuf:"bytes,3,opt,name=logger,proto3" json:"logger,omitempty"`
+	// Entry caller as file:line.
+	Caller string `protobuf:"bytes,4,opt,name=caller,proto3" json:"caller,omitempty"`
+	// Entry message.
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// Entry stacktrace.
+	Stack string `protobuf:"bytes,6,opt,name=stack,proto3" json:"stack,omitempty"`
+	// Entry fields, the values are json encoded.
+	Fields               map[string]string `protobuf:"bytes,7,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *LogEntry) Reset()         { *m = LogEntry{} }
+func (m *LogEntry) String() string { return proto.CompactTextString(m) }
+func (*LogEntry) ProtoMessage()    {}
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_515c2b830bcedeab, []int{0}
+}
+
+func (m *LogEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogEntry.Unmarshal(m, b)
+}
+func (m *LogEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogEntry.Marshal(b, m, deterministic)
+}
+func (m *LogEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogEntry.Merge(m, src)
+}
+func (m *LogEntry) XXX_Size() int {
+	return xxx_messageInfo_LogEntry.Size(m)
+}
+func (m *LogEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogEntry proto.InternalMessageInfo
+
+func (m *LogEntry) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *LogEntry) GetLevel() Level {
+	if m != nil {
+		return m.Level
+	}
+	return Level_Debug
+}
+
+func (m *LogEntry) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *LogEntry) GetCaller() string {
+	if m != nil {
+		return m.Caller
+	}
+	return ""
+}
+
+func (m *LogEntry) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *LogEntry) GetStack() string {
+	if m != nil {
+		return m.Stack
+	}
+	return ""
+}
+
+func (m *LogEntry) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
+// Push response message.
+type PushResponse struct {
+	// Received entries count.
+	Count                uint64   `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PushResponse) Reset()         { *m = PushResponse{} }
+func (m *PushResponse) String() string { return proto.CompactTextString(m) }
+func (*PushResponse) ProtoMessage()    {}
+func (*PushResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_515c2b830bcedeab, []int{1}
+}
+
+func (m *PushResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PushResponse.Unmarshal(m, b)
+}
+func (m *PushResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PushResponse.Marshal(b, m, deterministic)
+}
+func (m *PushResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PushResponse.Merge(m, src)
+}
+func (m *PushResponse) XXX_Size() int {
+	return xxx_messageInfo_PushResponse.Size(m)
+}
+func (m *PushResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PushResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PushResponse proto.InternalMessageInfo
+
+func (m *PushResponse) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("zap.Level", Level_name, Level_value)
+	proto.RegisterType((*LogEntry)(nil), "zap.LogEntry")
+	proto.RegisterMapType((map[string]string)(nil), "zap.LogEntry.FieldsEntry")
+	proto.RegisterType((*PushResponse)(nil), "zap.PushResponse")
+}
+
+func init() { proto.RegisterFile("collector/collector.proto", fileDescriptor_515c2b830bcedeab) }
+
+var fileDescriptor_515c2b830bcedeab = []byte{
+	// 369 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x91, 0x5f, 0xcf, 0xd2, 0x30,
+	0x18, 0xc5, 0xdd, 0xdf, 0xf7, 0xdd, 0x03, 0x9a, 0xd9, 0x18, 0x53, 0xb8, 0x5a, 0x88, 0xc6, 0x85,
+	0xc4, 0x11, 0xf1, 0x46, 0xb9, 0x54, 0x20, 0x31, 0xe1, 0x82, 0xd4, 0x0b, 0x13, 0xef, 0xca, 0x2c,
+	0x65, 0xa1, 0x5b, 0x97, 0xae, 0xc3, 0xc0, 0x87, 0xf0, 0x33, 0x9b, 0xb6, 0xa0, 0xbc, 0x77, 0xe7,
+	0x77, 0x4e, 0x9b, 0x9e, 0xa7, 0x0f, 0x8c, 0x4a, 0x29, 0x04, 0x2b, 0xb5, 0x54, 0xb3, 0x7f, 0xaa,
+	0x68, 0x95, 0xd4, 0x12, 0x05, 0x17, 0xda, 0x4e, 0xfe, 0xf8, 0xf0, 0xb8, 0x91, 0x7c, 0xd5, 0x68,
+	0x75, 0x46, 0x08, 0x42, 0x5d, 0xd5, 0x0c, 0x7b, 0x99, 0x97, 0x07, 0xc4, 0x6a, 0x94, 0x41, 0x24,
+	0xd8, 0x89, 0x09, 0xec, 0x67, 0x5e, 0xfe, 0x62, 0x0e, 0xc5, 0x85, 0xb6, 0xc5, 0xc6, 0x38, 0xc4,
+	0x05, 0xe8, 0x35, 0xc4, 0x42, 0x72, 0xce, 0x14, 0x0e, 0x32, 0x2f, 0x4f, 0xc8, 0x95, 0x8c, 0x5f,
+	0x52, 0x21, 0x98, 0xc2, 0xa1, 0xf3, 0x1d, 0x21, 0x0c, 0x0f, 0x35, 0xeb, 0x3a, 0xca, 0x19, 0x8e,
+	0x6c, 0x70, 0x43, 0xf4, 0x0a, 0xa2, 0x4e, 0xd3, 0xf2, 0x88, 0x63, 0xeb, 0x3b, 0x40, 0x1f, 0x20,
+	0xde, 0x57, 0x4c, 0xfc, 0xea, 0xf0, 0x43, 0x16, 0xe4, 0x83, 0xf9, 0xc8, 0x55, 0xb8, 0x96, 0x2e,
+	0xd6, 0x36, 0xb3, 0x9a, 0x5c, 0x0f, 0x8e, 0x3f, 0xc3, 0xe0, 0xce, 0x46, 0x29, 0x04, 0x47, 0x76,
+	0xb6, 0x63, 0x25, 0xc4, 0x48, 0xf3, 0xd2, 0x89, 0x8a, 0x9e, 0xd9, 0xa9, 0x12, 0xe2, 0x60, 0xe1,
+	0x7f, 0xf2, 0x26, 0x6f, 0x60, 0xb8, 0xed, 0xbb, 0x03, 0x61, 0x5d, 0x2b, 0x9b, 0xce, 0x76, 0x2a,
+	0x65, 0xdf, 0x68, 0x7b, 0x3b, 0x24, 0x0e, 0xa6, 0xdf, 0x21, 0xb2, 0x7f, 0x80, 0x12, 0x88, 0x96,
+	0x6c, 0xd7, 0xf3, 0xf4, 0x19, 0x7a, 0x84, 0xf0, 0x5b, 0xb3, 0x97, 0xa9, 0x67, 0xd4, 0x0f, 0xaa,
+	0x9a, 0xd4, 0x37, 0xf1, 0x4a, 0x29, 0xa9, 0xd2, 0x00, 0x01, 0xc4, 0xcb, 0x2d, 0x6d, 0xaa, 0x32,
+	0x0d, 0x8d, 0xed, 0x64, 0x64, 0xe4, 0x9a, 0x6a, 0x2a, 0xd2, 0x78, 0xbe, 0x80, 0xe1, 0x46, 0xf2,
+	0xaf, 0xb7, 0x35, 0xa1, 0x29, 0x84, 0xa6, 0x0a, 0x7a, 0xfe, 0x64, 0xe0, 0xf1, 0x4b, 0x8b, 0xf7,
+	0x25, 0x73, 0xef, 0xcb, 0xbb, 0x9f, 0x6f, 0x79, 0xa5, 0x0f, 0xfd, 0xae, 0x28, 0x65, 0x3d, 0xe3,
+	0xf2, 0xfd, 0x5e, 0xd1, 0x9a, 0xfd, 0x96, 0xea, 0x38, 0xbb, 0xd0, 0xf6, 0xff, 0xee, 0x77, 0xb1,
+	0x5d, 0xfe, 0xc7, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xcc, 0x67, 0xb3, 0x3d, 0x19, 0x02, 0x00,
+	0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// LogCollectorClient is the client API for LogCollector service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LogCollectorClient interface {
+	// Push log entries, the response acknowledges the received entries count.
+	Push(ctx context.Context, opts ...grpc.CallOption) (LogCollector_PushClient, error)
+}
+
+type logCollectorClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLogCollectorClient(cc *grpc.ClientConn) LogCollectorClient {
+	return &logCollectorClient{cc}
+}
+
+func (c *logCollectorClient) Push(ctx context.Context, opts ...grpc.CallOption) (LogCollector_PushClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogCollector_serviceDesc.Streams[0], "/zap.LogCollector/Push", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logCollectorPushClient{stream}
+	return x, nil
+}
+
+type LogCollector_PushClient interface {
+	Send(*LogEntry) error
+	CloseAndRecv() (*PushResponse, error)
+	grpc.ClientStream
+}
+
+type logCollectorPushClient struct {
+	grpc.ClientStream
+}
+
+func (x *logCollectorPushClient) Send(m *LogEntry) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *logCollectorPushClient) CloseAndRecv() (*PushResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(PushResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogCollectorServer is the server API for LogCollector service.
+type LogCollectorServer interface {
+	// Push log entries, the response acknowledges the received entries count.
+	Push(LogCollector_PushServer) error
+}
+
+func RegisterLogCollectorServer(s *grpc.Server, srv LogCollectorServer) {
+	s.RegisterService(&_LogCollector_serviceDesc, srv)
+}
+
+func _LogCollector_Push_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogCollectorServer).Push(&logCollectorPushServer{stream})
+}
+
+type LogCollector_PushServer interface {
+	SendAndClose(*PushResponse) error
+	Recv() (*LogEntry, error)
+	grpc.ServerStream
+}
+
+type logCollectorPushServer struct {
+	grpc.ServerStream
+}
+
+func (x *logCollectorPushServer) SendAndClose(m *PushResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *logCollectorPushServer) Recv() (*LogEntry, error) {
+	m := new(LogEntry)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _LogCollector_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "zap.LogCollector",
+	HandlerType: (*LogCollectorServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Push",
+			Handler:       _LogCollector_Push_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "collector/collector.proto",
+}
@@ -0,0 +1,36 @@
+package collector
+
+import (
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Proto levels mapping to zap levels.
+var protoLevels = map[Level]zapcore.Level{
+	Level_Debug:  zapcore.DebugLevel,
+	Level_Info:   zapcore.InfoLevel,
+	Level_Warn:   zapcore.WarnLevel,
+	Level_Error:  zapcore.ErrorLevel,
+	Level_DPanic: zapcore.DPanicLevel,
+	Level_Panic:  zapcore.PanicLevel,
+	Level_Fatal:  zapcore.FatalLevel,
+}
+
+// Convert proto level to zap level, return InvalidArgument error when level is unknown.
+func LevelToZap(level Level) (zapcore.Level, error) {
+	if l, ok := protoLevels[level]; ok {
+		return l, nil
+	}
+	return zapcore.InfoLevel, status.Errorf(codes.InvalidArgument, "unknown level %d", level)
+}
+
+// Convert zap level to proto level, return InvalidArgument error when level is unknown.
+func LevelFromZap(level zapcore.Level) (Level, error) {
+	for l, zl := range protoLevels {
+		if zl == level {
+			return l, nil
+		}
+	}
+	return Level_Info, status.Errorf(codes.InvalidArgument, "unknown level %d", level)
+}
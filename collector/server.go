@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/peer"
+)
+
+// Reference log collector server, received entries are written into the core of the local logger
+// with their own logger name, time, caller and stacktrace.
+type Server struct {
+	// local logger.
+	logger *zap.Logger
+	// peer address field key, empty is disabled.
+	peerKey string
+}
+
+// New log collector server writes entries into logger.
+func NewServer(logger *zap.Logger) *Server {
+	return &Server{logger: logger}
+}
+
+// Set peer address field key, received entries are logged with the peer address.
+func (s *Server) SetPeerKey(key string) *Server {
+	s.peerKey = key
+	return s
+}
+
+// Implement LogCollectorServer interface.
+func (s *Server) Push(stream LogCollector_PushServer) error {
+	logger := s.logger
+	if s.peerKey != "" {
+		if p, ok := peer.FromContext(stream.Context()); ok {
+			logger = logger.With(zap.String(s.peerKey, p.Addr.String()))
+		}
+	}
+
+	var count uint64
+	for {
+		entry, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&PushResponse{Count: count})
+		}
+		if err != nil {
+			return err
+		}
+
+		write(logger, entry)
+		count++
+	}
+}
+
+// write entry into logger, panic and fatal entries are logged without panic or exit.
+func write(logger *zap.Logger, entry *LogEntry) {
+	level, err := LevelToZap(entry.Level)
+	if err != nil {
+		level = zapcore.InfoLevel
+	}
+
+	e := zapcore.Entry{
+		Level:      level,
+		Time:       time.Unix(0, entry.Time),
+		LoggerName: entry.Logger,
+		Message:    entry.Message,
+		Caller:     parseCaller(entry.Caller),
+		Stack:      entry.Stack,
+	}
+	if entry.Time == 0 {
+		e.Time = time.Now()
+	}
+
+	if ce := logger.Core().Check(e, nil); ce != nil {
+		ce.Write(Fields(entry.Fields)...)
+	}
+}
+
+// parse caller as file:line.
+func parseCaller(caller string) zapcore.EntryCaller {
+	if caller == "" {
+		return zapcore.EntryCaller{}
+	}
+
+	n := strings.LastIndexByte(caller, ':')
+	if n < 0 {
+		return zapcore.EntryCaller{Defined: true, File: caller}
+	}
+
+	line, err := strconv.Atoi(caller[n+1:])
+	if err != nil {
+		return zapcore.EntryCaller{Defined: true, File: caller}
+	}
+
+	return zapcore.EntryCaller{Defined: true, File: caller[:n], Line: line}
+}
+
+// Convert entry fields to zap fields sorted by key, the json encoded values are decoded,
+// invalid json values are kept as strings.
+func Fields(fields map[string]string) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fs := make([]zap.Field, 0, len(fields))
+	for _, k := range keys {
+		v := fields[k]
+		var value interface{}
+		if jsoniter.UnmarshalFromString(v, &value) == nil {
+			fs = append(fs, zap.Any(k, value))
+		} else {
+			fs = append(fs, zap.String(k, v))
+		}
+	}
+
+	return fs
+}
+
+// Convert values to entry fields of json encoded values, the value types are kept by Fields.
+func EncodeFields(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]string, len(values))
+	for k, v := range values {
+		if s, err := jsoniter.MarshalToString(v); err == nil {
+			fields[k] = s
+		}
+	}
+
+	return fields
+}
+
+// Convert values to entry fields, string values are kept as is, others are json encoded.
+func FieldsOf(values map[string]interface{}) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]string, len(values))
+	for k, v := range values {
+		if s, ok := v.(string); ok {
+			fields[k] = s
+		} else if s, err := jsoniter.MarshalToString(v); err == nil {
+			fields[k] = s
+		}
+	}
+
+	return fields
+}
@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"sync"
 	"time"
 	"unsafe"
 
 	"github.com/json-iterator/go"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/go-framework/zap/encoder"
 	"github.com/go-framework/zap/syncer"
 )
 
@@ -29,6 +32,44 @@ type Config struct {
 	Console bool `json:"console" yaml:"console"`
 	// Logger fields.
 	Fields map[string]interface{} `json:"fields" yaml:"fields"`
+	// Logger encoder config, overrides the development or production encoder config.
+	Encoder *encoder.Config `json:"encoder,omitempty" yaml:"encoder"`
+	// Logger sampling, default is depend on logger development mode.
+	Sampling *SamplingConfig `json:"sampling,omitempty" yaml:"sampling"`
+	// Logger caller, default is enabled.
+	Caller *CallerConfig `json:"caller,omitempty" yaml:"caller"`
+	// Logger stacktrace level, default is warn in development mode and error in production mode.
+	StacktraceLevel *zapcore.Level `json:"stacktrace_level,omitempty" yaml:"stacktrace_level"`
+	// Disable stacktrace.
+	DisableStacktrace bool `json:"disable_stacktrace,omitempty" yaml:"disable_stacktrace"`
+	// Logger levels by logger name prefix, overrides the level for the named loggers.
+	// levels: {db: debug, http.client: warn}
+	Levels *LevelTree `json:"levels,omitempty" yaml:"levels"`
+
+	// level overrides with ttl by LevelService.
+	overrides *levelOverrides
+	// tail hub of LogService.
+	tail *tailHub
+	// core built by NewZapLogger, reused to log the signal actions.
+	core zapcore.Core
+}
+
+// Sampling config, sampling is disabled when initial or thereafter is not positive.
+type SamplingConfig struct {
+	// Sampling tick, default is one second.
+	Tick time.Duration `json:"tick" yaml:"tick"`
+	// Log the first initial entries with the same level and message in every tick.
+	Initial int `json:"initial" yaml:"initial"`
+	// Log every thereafter entry after the first initial entries in every tick.
+	Thereafter int `json:"thereafter" yaml:"thereafter"`
+}
+
+// Caller config.
+type CallerConfig struct {
+	// Enable caller.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Caller skip.
+	Skip int `json:"skip" yaml:"skip"`
 }
 
 // Implement Stringer.
@@ -40,105 +81,308 @@ func (c *Config) String() string {
 	return fmt.Sprintf("level: %s development: %t console: %t", c.Level.Level(), c.Development, c.Console)
 }
 
-// Clone.
+// Clone config deeply, the clone has independent level, fields and writes,
+// writes are cloned through syncer.Cloner.
 func (c *Config) Clone() *Config {
 	config := *c
+
+	// independent level.
+	if c.Level != (zap.AtomicLevel{}) {
+		config.Level = zap.NewAtomicLevelAt(c.Level.Level())
+	}
+
+	// copy fields.
+	if c.Fields != nil {
+		config.Fields = make(map[string]interface{}, len(c.Fields))
+		for k, v := range c.Fields {
+			config.Fields[k] = v
+		}
+	}
+
+	// clone writes.
+	if c.Writes != nil {
+		config.Writes = make([]*syncer.Write, 0, len(c.Writes))
+		for _, write := range c.Writes {
+			config.Writes = append(config.Writes, write.Clone())
+		}
+	}
+
+	if c.Encoder != nil {
+		e := *c.Encoder
+		config.Encoder = &e
+	}
+	if c.Sampling != nil {
+		sampling := *c.Sampling
+		config.Sampling = &sampling
+	}
+	if c.Caller != nil {
+		caller := *c.Caller
+		config.Caller = &caller
+	}
+	if c.StacktraceLevel != nil {
+		level := *c.StacktraceLevel
+		config.StacktraceLevel = &level
+	}
+	config.Levels = c.Levels.Clone()
+	config.overrides = nil
+	config.tail = nil
+	config.core = nil
+
 	return &config
 }
 
-// New zap logger.
+// Clone config deeply but link the atomic level and level tree to the original,
+// changing the level of one changes both.
+func (c *Config) CloneLinked() *Config {
+	config := c.Clone()
+	config.Level = c.Level
+	config.Levels = c.Levels
+	return config
+}
+
+// New zap logger, the config errors are reported to stderr and the invalid settings are ignored,
+// use Build to get the errors.
 func (c *Config) NewZapLogger(opts ...zap.Option) *zap.Logger {
-	// zap config.
-	var config zap.Config
-	// zap encoder.
-	var enc zapcore.Encoder
+	core, err := c.newCore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v zap config error: %v\n", time.Now(), err)
+	}
+
+	builtCoreMutex.Lock()
+	c.core = core
+	builtCoreMutex.Unlock()
+
+	// new zap logger.
+	logger := zap.New(core)
+
+	return logger.WithOptions(c.newOptions(opts)...)
+}
 
-	// default stack level.
-	stackLevel := zap.ErrorLevel
+// Guard the built core of config.
+var builtCoreMutex sync.Mutex
 
+// get the logger of the core built by NewZapLogger, nop logger when the core is not built.
+func (c *Config) builtLogger() *zap.Logger {
+	builtCoreMutex.Lock()
+	defer builtCoreMutex.Unlock()
+
+	if c.core == nil {
+		return zap.NewNop()
+	}
+	return zap.New(c.core)
+}
+
+// get zap preset config, encoding and stack level by development mode.
+func (c *Config) preset() (config zap.Config, encoding string, stackLevel zapcore.Level) {
 	// zap mode.
 	if c.Development {
-		opts = append(opts, zap.Development())
 		config = zap.NewDevelopmentConfig()
-		enc = zapcore.NewConsoleEncoder(config.EncoderConfig)
+		encoding = encoder.Console
 		stackLevel = zap.WarnLevel
 	} else {
 		config = zap.NewProductionConfig()
-		enc = zapcore.NewJSONEncoder(config.EncoderConfig)
+		encoding = encoder.JSON
+		stackLevel = zap.ErrorLevel
+	}
+
+	return
+}
+
+// new zap options, includes development, caller and stacktrace.
+func (c *Config) newOptions(opts []zap.Option) []zap.Option {
+	config, _, stackLevel := c.preset()
+
+	// zap mode.
+	if c.Development {
+		opts = append(opts, zap.Development())
+	}
+
+	// caller config.
+	caller := CallerConfig{Enabled: !config.DisableCaller}
+	if c.Caller != nil {
+		caller = *c.Caller
 	}
 
 	// enable caller.
-	if !config.DisableCaller {
+	if caller.Enabled {
 		opts = append(opts, zap.AddCaller())
+		if caller.Skip != 0 {
+			opts = append(opts, zap.AddCallerSkip(caller.Skip))
+		}
+	}
+
+	// stack level.
+	if c.StacktraceLevel != nil {
+		stackLevel = *c.StacktraceLevel
 	}
 
 	// enable stack trace .
-	if !config.DisableStacktrace {
+	if !config.DisableStacktrace && !c.DisableStacktrace {
 		opts = append(opts, zap.AddStacktrace(stackLevel))
 	}
 
-	// config sampling.
-	if config.Sampling != nil {
-		opts = append(opts, zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-			return zapcore.NewSampler(core, time.Second, int(config.Sampling.Initial), int(config.Sampling.Thereafter))
-		}))
-	}
+	return opts
+}
 
-	// initial fields.
-	if len(config.InitialFields) > 0 {
-		fs := make([]zap.Field, 0, len(config.InitialFields))
-		keys := make([]string, 0, len(config.InitialFields))
-		for k := range config.InitialFields {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			fs = append(fs, zap.Any(k, config.InitialFields[k]))
-		}
-		opts = append(opts, zap.Fields(fs...))
+// new zap core, includes writers, fields and sampling,
+// the core is built with the valid settings when error is returned.
+func (c *Config) newCore() (zapcore.Core, error) {
+	var errs []error
+
+	config, encoding, _ := c.preset()
+
+	// encoder config overrides.
+	if err := c.Encoder.Apply(&config.EncoderConfig); err != nil {
+		errs = append(errs, fmt.Errorf("encoder: %v", err))
 	}
 
-	// set fields.
-	if len(c.Fields) > 0 {
-		fs := make([]zap.Field, 0, len(c.Fields))
-		keys := make([]string, 0, len(c.Fields))
-		for k := range c.Fields {
-			keys = append(keys, k)
-		}
-		sort.Strings(keys)
-		for _, k := range keys {
-			fs = append(fs, zap.Any(k, c.Fields[k]))
-		}
-		opts = append(opts, zap.Fields(fs...))
+	// level tree for runtime adjustment.
+	if c.Levels == nil {
+		c.Levels = NewLevelTree(nil)
 	}
 
-	// multiple write syncer.
-	var ws []zapcore.WriteSyncer
+	// multiple cores, the logger level is enforced by level core.
+	var cores []zapcore.Core
 
 	// enable stdout.
 	if c.Console {
-		ws = append(ws, os.Stdout)
+		enc, _ := newEncoder(encoding, config.EncoderConfig)
+		cores = append(cores, zapcore.NewCore(
+			enc,
+			os.Stdout,
+			zapcore.DebugLevel,
+		))
 	}
 
-	// enable Writes.
-	if len(c.Writes) != 0 {
-		// append writer.
-		for _, writer := range c.Writes {
-			ws = append(ws, zapcore.AddSync(writer.GetWriter()))
+	// enable Writes, every write has own level, encoding and encoder.
+	for i, writer := range c.Writes {
+		encoderConfig := config.EncoderConfig
+		if err := writer.Encoder.Apply(&encoderConfig); err != nil {
+			errs = append(errs, fmt.Errorf("writes[%d].encoder: %v", i, err))
+		}
+
+		enc, err := newEncoder(writer.GetEncoding(encoding), encoderConfig)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("writes[%d].%v", i, err))
+		}
+
+		// entry parser writer parses entries by the effective encoder config.
+		if parser, ok := writer.GetWriter().(syncer.EntryParser); ok {
+			parser.SetEncoderConfig(encoderConfig)
 		}
+
+		// start writer after the config is decoded and validated.
+		if err := writer.Start(); err != nil {
+			errs = append(errs, fmt.Errorf("writes[%d].%v", i, err))
+		}
+
+		cores = append(cores, zapcore.NewCore(
+			enc,
+			zapcore.AddSync(writer.GetWriter()),
+			writer.LevelEnabler(nil),
+		))
 	}
 
+	// tail subscribers, disabled without subscriber.
+	cores = append(cores, newTailCore(c.tailHub()))
+
 	// new zap core.
-	core := zapcore.NewCore(
-		enc,
-		zapcore.NewMultiWriteSyncer(ws...),
-		c.Level,
-	)
+	core := zapcore.NewTee(cores...)
 
-	// new zap logger.
-	logger := zap.New(core)
+	// sampling config.
+	var sampling SamplingConfig
+	if config.Sampling != nil {
+		sampling = SamplingConfig{Tick: time.Second, Initial: config.Sampling.Initial, Thereafter: config.Sampling.Thereafter}
+	}
+	if c.Sampling != nil {
+		sampling = *c.Sampling
+	}
+	if sampling.Tick <= 0 {
+		sampling.Tick = time.Second
+	}
+
+	// config sampling.
+	if sampling.Initial > 0 && sampling.Thereafter > 0 {
+		core = zapcore.NewSampler(core, sampling.Tick, sampling.Initial, sampling.Thereafter)
+	}
+
+	// enforce logger level and level tree.
+	core = newLevelCore(core, c.Level, c.Levels)
+
+	// initial fields.
+	if fs := sortedFields(config.InitialFields); len(fs) > 0 {
+		core = core.With(fs)
+	}
+
+	// set fields.
+	if fs := sortedFields(c.Fields); len(fs) > 0 {
+		core = core.With(fs)
+	}
 
-	return logger.WithOptions(opts...)
+	return core, multierr.Combine(errs...)
+}
+
+// get fields sorted by key.
+func sortedFields(fields map[string]interface{}) []zap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	fs := make([]zap.Field, 0, len(fields))
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fs = append(fs, zap.Any(k, fields[k]))
+	}
+
+	return fs
+}
+
+// new zap encoder with encoding, fallback to json encoder when encoding is not supported,
+// the error is prefixed by field name.
+func newEncoder(encoding string, config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	enc, err := encoder.New(encoding, config)
+	if err != nil {
+		return zapcore.NewJSONEncoder(config), fmt.Errorf("encoding: %v", err)
+	}
+
+	return enc, nil
+}
+
+// Set encoder config.
+func (c *Config) SetEncoder(config *encoder.Config) *Config {
+	c.Encoder = config
+	return c
+}
+
+// Set sampling.
+func (c *Config) SetSampling(tick time.Duration, initial, thereafter int) *Config {
+	c.Sampling = &SamplingConfig{Tick: tick, Initial: initial, Thereafter: thereafter}
+	return c
+}
+
+// Set caller.
+func (c *Config) SetCaller(enabled bool, skip int) *Config {
+	c.Caller = &CallerConfig{Enabled: enabled, Skip: skip}
+	return c
+}
+
+// Set stacktrace level.
+func (c *Config) SetStacktraceLevel(level zapcore.Level) *Config {
+	c.StacktraceLevel = &level
+	return c
+}
+
+// Set level of logger name prefix.
+func (c *Config) SetNamedLevel(name string, level zapcore.Level) *Config {
+	if c.Levels == nil {
+		c.Levels = NewLevelTree(nil)
+	}
+	c.Levels.Set(name, level)
+	return c
 }
 
 // Add syncer write.
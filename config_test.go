@@ -1,10 +1,19 @@
 package zap
 
 import (
+	"bytes"
 	"io/ioutil"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/lumberjack"
 )
 
 func TestConfig_UnmarshalYAML(t *testing.T) {
@@ -25,3 +34,103 @@ func TestConfig_UnmarshalYAML(t *testing.T) {
 
 	t.Log("config", config)
 }
+
+func TestConfig_WritesLevel(t *testing.T) {
+	config := GetDebugConfig()
+	config.Console = false
+
+	all := &bytes.Buffer{}
+	errs := &bytes.Buffer{}
+
+	config.AddSyncerWrite((&syncer.Write{Config: all}).SetEncoding(encoder.JSON))
+	config.AddSyncerWrite((&syncer.Write{Config: errs}).SetLevel(zap.ErrorLevel).SetEncoding(encoder.Console))
+
+	logger := config.NewZapLogger()
+	logger.Debug("debug")
+	logger.Error("error")
+
+	if n := strings.Count(all.String(), "\n"); n != 2 {
+		t.Fatalf("all write should have 2 lines, got %d: %s", n, all.String())
+	}
+	if !strings.HasPrefix(all.String(), "{") {
+		t.Fatalf("all write should be json encoding: %s", all.String())
+	}
+	if strings.Contains(errs.String(), "debug") || !strings.Contains(errs.String(), "error") {
+		t.Fatalf("errs write should have only error line: %s", errs.String())
+	}
+	if strings.HasPrefix(errs.String(), "{") {
+		t.Fatalf("errs write should be console encoding: %s", errs.String())
+	}
+}
+
+func TestConfig_String(t *testing.T) {
+	config := GetDefaultConfig().
+		SetSampling(time.Minute, 10, 100).
+		SetCaller(true, 1).
+		SetStacktraceLevel(zap.DPanicLevel)
+	config.DisableStacktrace = true
+
+	t.Log("config", config)
+
+	c := &Config{}
+	if err := jsoniter.UnmarshalFromString(config.String(), c); err != nil {
+		t.Fatal(err)
+	}
+
+	if *c.Sampling != *config.Sampling || *c.Caller != *config.Caller ||
+		*c.StacktraceLevel != *config.StacktraceLevel || !c.DisableStacktrace {
+		t.Fatalf("config round trip failed: %s", c)
+	}
+}
+
+func TestConfig_Clone(t *testing.T) {
+	config := GetDefaultConfig().
+		AddFields("app", "test").
+		SetEncoder(&encoder.Config{MessageKey: "message"}).
+		SetSampling(time.Second, 10, 100).
+		SetCaller(true, 1).
+		SetStacktraceLevel(zap.ErrorLevel).
+		AddSyncerWrite((&syncer.Write{
+			Name:   lumberjack.Name,
+			Config: lumberjack.New("clone.log"),
+		}).SetLevel(zap.WarnLevel).SetMaxLevel(zap.ErrorLevel).SetEncoder(&encoder.Config{TimeKey: "time"}))
+
+	clone := config.Clone()
+
+	// mutate through the pointers, a shallow copy changes the original.
+	clone.Level.SetLevel(zap.ErrorLevel)
+	clone.Fields["app"] = "clone"
+	clone.Encoder.MessageKey = "msg"
+	clone.Sampling.Initial = 1
+	clone.Caller.Skip = 2
+	*clone.StacktraceLevel = zap.WarnLevel
+	*clone.Writes[0].Level = zap.ErrorLevel
+	*clone.Writes[0].MaxLevel = zap.FatalLevel
+	clone.Writes[0].Encoder.TimeKey = "ts"
+	clone.Writes[0].GetWriter().(*lumberjack.Logger).Filename = "other.log"
+
+	if config.Level.Level() != zap.InfoLevel {
+		t.Fatal("clone level should be independent")
+	}
+	if config.Fields["app"] != "test" {
+		t.Fatal("clone fields should be independent")
+	}
+	if config.Encoder.MessageKey != "message" || config.Sampling.Initial != 10 || config.Caller.Skip != 1 || *config.StacktraceLevel != zap.ErrorLevel {
+		t.Fatalf("clone encoder, sampling, caller and stacktrace level should be independent: %s", config)
+	}
+	if *config.Writes[0].Level != zap.WarnLevel || *config.Writes[0].MaxLevel != zap.ErrorLevel {
+		t.Fatal("clone write level should be independent")
+	}
+	if config.Writes[0].Encoder.TimeKey != "time" {
+		t.Fatal("clone write encoder should be independent")
+	}
+	if clone.Writes[0] == config.Writes[0] || config.Writes[0].GetWriter().(*lumberjack.Logger).Filename != "clone.log" {
+		t.Fatal("clone writes should be independent")
+	}
+
+	linked := config.CloneLinked()
+	linked.Level.SetLevel(zap.DebugLevel)
+	if config.Level.Level() != zap.DebugLevel {
+		t.Fatal("linked clone should share level")
+	}
+}
@@ -0,0 +1,226 @@
+package encoder
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// JSON encoding.
+	JSON = "json"
+	// Console encoding.
+	Console = "console"
+)
+
+// Omit key value, the key which set as OmitKey will be omitted in the output.
+const OmitKey = "-"
+
+// Encoder config, overrides the zap encoder config when the field is not empty.
+type Config struct {
+	// Message key.
+	MessageKey string `json:"message_key,omitempty" yaml:"message_key" mapstructure:"message_key"`
+	// Level key.
+	LevelKey string `json:"level_key,omitempty" yaml:"level_key" mapstructure:"level_key"`
+	// Time key.
+	TimeKey string `json:"time_key,omitempty" yaml:"time_key" mapstructure:"time_key"`
+	// Logger name key.
+	NameKey string `json:"name_key,omitempty" yaml:"name_key" mapstructure:"name_key"`
+	// Caller key.
+	CallerKey string `json:"caller_key,omitempty" yaml:"caller_key" mapstructure:"caller_key"`
+	// Stacktrace key.
+	StacktraceKey string `json:"stacktrace_key,omitempty" yaml:"stacktrace_key" mapstructure:"stacktrace_key"`
+	// Line ending.
+	LineEnding string `json:"line_ending,omitempty" yaml:"line_ending" mapstructure:"line_ending"`
+	// Time encoder: iso8601, rfc3339, rfc3339nano, epoch, epoch_millis and epoch_nanos.
+	TimeEncoder string `json:"time_encoder,omitempty" yaml:"time_encoder" mapstructure:"time_encoder"`
+	// Time layout, encode time with the layout format, has priority over TimeEncoder.
+	TimeLayout string `json:"time_layout,omitempty" yaml:"time_layout" mapstructure:"time_layout"`
+	// Level encoder: lowercase, lowercaseColor, capital and capitalColor.
+	LevelEncoder string `json:"level_encoder,omitempty" yaml:"level_encoder" mapstructure:"level_encoder"`
+	// Duration encoder: seconds, millis, nanos and string.
+	DurationEncoder string `json:"duration_encoder,omitempty" yaml:"duration_encoder" mapstructure:"duration_encoder"`
+	// Caller encoder: short and full.
+	CallerEncoder string `json:"caller_encoder,omitempty" yaml:"caller_encoder" mapstructure:"caller_encoder"`
+	// Name encoder: full.
+	NameEncoder string `json:"name_encoder,omitempty" yaml:"name_encoder" mapstructure:"name_encoder"`
+}
+
+// Time encoders.
+var timeEncoders = map[string]zapcore.TimeEncoder{
+	"iso8601":      zapcore.ISO8601TimeEncoder,
+	"rfc3339":      LayoutTimeEncoder(time.RFC3339),
+	"rfc3339nano":  LayoutTimeEncoder(time.RFC3339Nano),
+	"epoch":        zapcore.EpochTimeEncoder,
+	"epoch_millis": zapcore.EpochMillisTimeEncoder,
+	"epoch_nanos":  zapcore.EpochNanosTimeEncoder,
+}
+
+// Level encoders.
+var levelEncoders = map[string]zapcore.LevelEncoder{
+	"lowercase":      zapcore.LowercaseLevelEncoder,
+	"lowercaseColor": zapcore.LowercaseColorLevelEncoder,
+	"capital":        zapcore.CapitalLevelEncoder,
+	"capitalColor":   zapcore.CapitalColorLevelEncoder,
+}
+
+// Duration encoders.
+var durationEncoders = map[string]zapcore.DurationEncoder{
+	"seconds": zapcore.SecondsDurationEncoder,
+	"millis":  MillisDurationEncoder,
+	"nanos":   zapcore.NanosDurationEncoder,
+	"string":  zapcore.StringDurationEncoder,
+}
+
+// Caller encoders.
+var callerEncoders = map[string]zapcore.CallerEncoder{
+	"short": zapcore.ShortCallerEncoder,
+	"full":  zapcore.FullCallerEncoder,
+}
+
+// Name encoders.
+var nameEncoders = map[string]zapcore.NameEncoder{
+	"full": zapcore.FullNameEncoder,
+}
+
+// Supported encoder names by config key, e.g. time_encoder: epoch, epoch_millis, ...
+func Names() map[string][]string {
+	return map[string][]string{
+		"time_encoder":     sortedKeys(timeEncoders),
+		"level_encoder":    sortedKeys(levelEncoders),
+		"duration_encoder": sortedKeys(durationEncoders),
+		"caller_encoder":   sortedKeys(callerEncoders),
+		"name_encoder":     sortedKeys(nameEncoders),
+	}
+}
+
+// get sorted keys of encoders map.
+func sortedKeys(encoders interface{}) []string {
+	v := reflect.ValueOf(encoders)
+	keys := make([]string, 0, v.Len())
+	for _, key := range v.MapKeys() {
+		keys = append(keys, key.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Layout time encoder serializes a time.Time with the layout format.
+func LayoutTimeEncoder(layout string) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.Format(layout))
+	}
+}
+
+// Millis duration encoder serializes a time.Duration to a floating-point number of milliseconds elapsed.
+func MillisDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendFloat64(float64(d) / float64(time.Millisecond))
+}
+
+// override key.
+func overrideKey(dst *string, key string) {
+	switch key {
+	case "":
+	case OmitKey:
+		*dst = ""
+	default:
+		*dst = key
+	}
+}
+
+// Apply overrides to zap encoder config, every supported field is applied,
+// errors of the unsupported encoder names are combined.
+func (c *Config) Apply(config *zapcore.EncoderConfig) error {
+	if c == nil {
+		return nil
+	}
+
+	overrideKey(&config.MessageKey, c.MessageKey)
+	overrideKey(&config.LevelKey, c.LevelKey)
+	overrideKey(&config.TimeKey, c.TimeKey)
+	overrideKey(&config.NameKey, c.NameKey)
+	overrideKey(&config.CallerKey, c.CallerKey)
+	overrideKey(&config.StacktraceKey, c.StacktraceKey)
+
+	if c.LineEnding != "" {
+		config.LineEnding = c.LineEnding
+	}
+
+	var errs []error
+
+	if c.TimeEncoder != "" {
+		if e, ok := timeEncoders[c.TimeEncoder]; ok {
+			config.EncodeTime = e
+		} else {
+			errs = append(errs, errors.New("not support time encoder: "+c.TimeEncoder))
+		}
+	}
+	if c.TimeLayout != "" {
+		config.EncodeTime = LayoutTimeEncoder(c.TimeLayout)
+	}
+
+	if c.LevelEncoder != "" {
+		if e, ok := levelEncoders[c.LevelEncoder]; ok {
+			config.EncodeLevel = e
+		} else {
+			errs = append(errs, errors.New("not support level encoder: "+c.LevelEncoder))
+		}
+	}
+
+	if c.DurationEncoder != "" {
+		if e, ok := durationEncoders[c.DurationEncoder]; ok {
+			config.EncodeDuration = e
+		} else {
+			errs = append(errs, errors.New("not support duration encoder: "+c.DurationEncoder))
+		}
+	}
+
+	if c.CallerEncoder != "" {
+		if e, ok := callerEncoders[c.CallerEncoder]; ok {
+			config.EncodeCaller = e
+		} else {
+			errs = append(errs, errors.New("not support caller encoder: "+c.CallerEncoder))
+		}
+	}
+
+	if c.NameEncoder != "" {
+		if e, ok := nameEncoders[c.NameEncoder]; ok {
+			config.EncodeName = e
+		} else {
+			errs = append(errs, errors.New("not support name encoder: "+c.NameEncoder))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Check encoder names are supported.
+func (c *Config) Check() error {
+	return c.Apply(&zapcore.EncoderConfig{})
+}
+
+// Check encoding is supported.
+func CheckEncoding(encoding string) error {
+	switch encoding {
+	case JSON, Console:
+		return nil
+	}
+
+	return errors.New("not support encoding: " + encoding)
+}
+
+// New zap encoder with encoding and encoder config.
+func New(encoding string, config zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	switch encoding {
+	case JSON:
+		return zapcore.NewJSONEncoder(config), nil
+	case Console:
+		return zapcore.NewConsoleEncoder(config), nil
+	}
+
+	return nil, errors.New("not support encoding: " + encoding)
+}
@@ -0,0 +1,92 @@
+package encoder
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConfig_Apply(t *testing.T) {
+	data := `
+message_key: message
+level_key: severity
+caller_key: "-"
+time_encoder: epoch_millis
+level_encoder: capitalColor
+duration_encoder: millis
+`
+	c := &Config{}
+	if err := yaml.Unmarshal([]byte(data), c); err != nil {
+		t.Fatal(err)
+	}
+
+	config := zapcore.EncoderConfig{TimeKey: "ts", CallerKey: "caller"}
+	if err := c.Apply(&config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.MessageKey != "message" || config.LevelKey != "severity" || config.CallerKey != "" {
+		t.Fatalf("keys not applied: %+v", config)
+	}
+
+	enc, err := New(JSON, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf, err := enc.EncodeEntry(zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Time:    time.Unix(1, 0),
+		Message: "hello",
+	}, []zapcore.Field{{Key: "d", Type: zapcore.DurationType, Integer: int64(time.Second)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expect := `{"severity":"\u001b[34mINFO\u001b[0m","ts":1000,"message":"hello","d":1000}` + "\n"
+	if buf.String() != expect {
+		t.Errorf("encoded entry %q, expect %q", buf.String(), expect)
+	}
+}
+
+func TestConfig_ApplyInvalid(t *testing.T) {
+	c := &Config{MessageKey: "message", TimeEncoder: "unknown", LevelEncoder: "capital", CallerEncoder: "long", NameEncoder: "full"}
+
+	config := zapcore.EncoderConfig{}
+	err := c.Apply(&config)
+	if err == nil {
+		t.Fatal("apply should fail")
+	}
+	for _, expect := range []string{"time encoder: unknown", "caller encoder: long"} {
+		if !strings.Contains(err.Error(), expect) {
+			t.Errorf("error should contain %q: %v", expect, err)
+		}
+	}
+
+	// the supported fields are applied.
+	if config.MessageKey != "message" || config.EncodeTime != nil || config.EncodeLevel == nil || config.EncodeCaller != nil || config.EncodeName == nil {
+		t.Errorf("supported fields should be applied: %+v", config)
+	}
+}
+
+func TestConfig_Check(t *testing.T) {
+	tests := []struct {
+		config *Config
+		valid  bool
+	}{
+		{&Config{TimeEncoder: "iso8601"}, true},
+		{&Config{TimeEncoder: "unknown"}, false},
+		{&Config{LevelEncoder: "upper"}, false},
+		{&Config{DurationEncoder: "string", CallerEncoder: "full", NameEncoder: "full"}, true},
+		{&Config{CallerEncoder: "long"}, false},
+	}
+
+	for _, test := range tests {
+		if err := test.config.Check(); (err == nil) != test.valid {
+			t.Errorf("config %+v check error: %v", test.config, err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package zap
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// Default environment variable prefix.
+	EnvPrefix = "LOG"
+)
+
+// Apply environment variable overrides with default prefix, see ApplyEnvPrefix.
+func (c *Config) ApplyEnv() error {
+	return c.ApplyEnvPrefix(EnvPrefix)
+}
+
+// Apply environment variable overrides with prefix on config:
+//
+//	LOG_LEVEL: logger level.
+//	LOG_DEVELOPMENT: logger development mode.
+//	LOG_CONSOLE: enable console logger.
+//	LOG_FIELDS_<KEY>: logger field with lowercase key.
+//	LOG_WRITES_<INDEX|NAME>_LEVEL: write minimum level.
+//	LOG_WRITES_<INDEX|NAME>_MAX_LEVEL: write maximum level.
+//	LOG_WRITES_<INDEX|NAME>_ENCODING: write encoding.
+//	LOG_WRITES_<INDEX|NAME>_CONFIG_<FIELD>: write config field.
+func (c *Config) ApplyEnvPrefix(prefix string) error {
+	prefix = strings.ToUpper(prefix) + "_"
+
+	for _, env := range os.Environ() {
+		n := strings.IndexByte(env, '=')
+		if n < 0 || !strings.HasPrefix(env[:n], prefix) {
+			continue
+		}
+
+		if err := c.applyEnv(strings.TrimPrefix(env[:n], prefix), env[n+1:]); err != nil {
+			return fmt.Errorf("environment variable %s: %v", env[:n], err)
+		}
+	}
+
+	return nil
+}
+
+// apply environment variable override.
+func (c *Config) applyEnv(key, value string) (err error) {
+	switch {
+	case key == "LEVEL":
+		if c.Level == (zap.AtomicLevel{}) {
+			c.Level = zap.NewAtomicLevel()
+		}
+		return c.Level.UnmarshalText([]byte(value))
+	case key == "DEVELOPMENT":
+		c.Development, err = strconv.ParseBool(value)
+	case key == "CONSOLE":
+		c.Console, err = strconv.ParseBool(value)
+	case strings.HasPrefix(key, "FIELDS_"):
+		c.AddFields(strings.ToLower(strings.TrimPrefix(key, "FIELDS_")), value)
+	case strings.HasPrefix(key, "WRITES_"):
+		return c.applyWriteEnv(strings.TrimPrefix(key, "WRITES_"), value)
+	}
+
+	return
+}
+
+// apply write environment variable override, the write is selected by index or name.
+func (c *Config) applyWriteEnv(key, value string) error {
+	n := strings.IndexByte(key, '_')
+	if n < 0 {
+		return fmt.Errorf("write override should be <INDEX|NAME>_<KEY>")
+	}
+	selector, key := key[:n], key[n+1:]
+
+	// select by index.
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(c.Writes) {
+			return fmt.Errorf("write index %d out of range", index)
+		}
+		return c.Writes[index].Override(key, value)
+	}
+
+	// select by name.
+	found := false
+	for _, write := range c.Writes {
+		if strings.EqualFold(write.Name, selector) {
+			found = true
+			if err := write.Override(key, value); err != nil {
+				return err
+			}
+		}
+	}
+	if !found {
+		return fmt.Errorf("write %s not found", strings.ToLower(selector))
+	}
+
+	return nil
+}
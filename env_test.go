@@ -0,0 +1,105 @@
+package zap
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestConfig_ApplyEnv(t *testing.T) {
+	os.Setenv("ZAP_TEST_DIR", "/tmp/zap")
+	os.Setenv("LOG_LEVEL", "warn")
+	os.Setenv("LOG_CONSOLE", "false")
+	os.Setenv("LOG_FIELDS_APP", "test")
+	os.Setenv("LOG_WRITES_0_LEVEL", "error")
+	os.Setenv("LOG_WRITES_LUMBERJACK_CONFIG_MAXBACKUPS", "20")
+	defer func() {
+		for _, key := range []string{"ZAP_TEST_DIR", "LOG_LEVEL", "LOG_CONSOLE", "LOG_FIELDS_APP", "LOG_WRITES_0_LEVEL", "LOG_WRITES_LUMBERJACK_CONFIG_MAXBACKUPS"} {
+			os.Unsetenv(key)
+		}
+	}()
+
+	config := &Config{}
+	err := yaml.Unmarshal([]byte(`
+level: debug
+console: true
+writes:
+  - name: lumberjack
+    config:
+      filename: ${ZAP_TEST_DIR}/app.log
+      maxsize: ${ZAP_TEST_MAXSIZE:-100}
+`), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.ApplyEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("config", config)
+
+	l := config.Writes[0].GetWriter().(*lumberjack.Logger)
+	if l.Filename != "/tmp/zap/app.log" || l.MaxSize != 100 || l.MaxBackups != 20 {
+		t.Fatalf("unexpected lumberjack config: %+v", l)
+	}
+	if config.Level.Level() != zap.WarnLevel || config.Console || config.Fields["app"] != "test" {
+		t.Fatalf("unexpected config: %s", config)
+	}
+	if *config.Writes[0].Level != zap.ErrorLevel {
+		t.Fatalf("unexpected write level: %s", config.Writes[0].Level)
+	}
+}
+
+func TestConfigWatcher_ReloadEnv(t *testing.T) {
+	os.Setenv("LOG_LEVEL", "warn")
+	os.Setenv("LOG_WRITES_0_LEVEL", "error")
+	defer os.Unsetenv("LOG_LEVEL")
+	defer os.Unsetenv("LOG_WRITES_0_LEVEL")
+
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	config := func(level string) string {
+		return `
+level: ` + level + `
+writes:
+  - name: lumberjack
+    level: ` + level + `
+    config:
+      filename: ` + filepath.Join(dir, "a.log")
+	}
+
+	writeFile(t, filename, config("debug"))
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// the overrides are applied on load and reload.
+	for i, level := range []string{"debug", "info"} {
+		if i > 0 {
+			writeFile(t, filename, config(level))
+			if err := w.Reload(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		c := w.Config()
+		if c.Level.Level() != zap.WarnLevel || *c.Writes[0].Level != zap.ErrorLevel {
+			t.Errorf("env overrides should be applied with file level %s: %s", level, c)
+		}
+	}
+}
@@ -1,9 +1,178 @@
 package zap
 
-import "flag"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
 
-// Register config flag.
+	"go.uber.org/zap"
+
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+// Register config flag to command line flag set.
 func (c *Config) RegisterFlag() {
-	flag.BoolVar(&c.Development, "log-dev", false, "logger development")
-	flag.BoolVar(&c.Console, "log-console", false, "logger console")
+	c.RegisterFlagSet(flag.CommandLine)
+}
+
+// Register config flag to flag set, -log-config should be in front of other log flags
+// because the loaded config replaces the previous flag values.
+func (c *Config) RegisterFlagSet(fs *flag.FlagSet) {
+	fs.Var(&configFlag{config: c}, "log-config", "logger config file path, yaml or json")
+	fs.Var(&levelFlag{config: c}, "log-level", "logger level: debug, info, warn, error, dpanic, panic, and fatal")
+	fs.BoolVar(&c.Development, "log-dev", c.Development, "logger development")
+	fs.BoolVar(&c.Console, "log-console", c.Console, "logger console")
+	fs.Var(&fieldsFlag{config: c}, "log-fields", "logger field as key=value, can be repeated")
+	fs.Var(&fileFlag{config: c}, "log-file", "logger lumberjack file, can be repeated")
+}
+
+// New flag set with config flags, use it with pflag FlagSet.AddGoFlagSet in cobra based binaries.
+func (c *Config) NewFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	c.RegisterFlagSet(fs)
+	return fs
+}
+
+// Level flag bound to the config atomic level.
+type levelFlag struct {
+	config *Config
+}
+
+// Implement flag Value interface.
+func (f *levelFlag) String() string {
+	if f.config == nil || f.config.Level == (zap.AtomicLevel{}) {
+		return ""
+	}
+	return f.config.Level.String()
+}
+
+// Implement flag Value interface.
+func (f *levelFlag) Set(value string) error {
+	if f.config.Level == (zap.AtomicLevel{}) {
+		f.config.Level = zap.NewAtomicLevel()
+	}
+	return f.config.Level.UnmarshalText([]byte(value))
+}
+
+// Implement pflag Value interface.
+func (f *levelFlag) Type() string {
+	return "level"
+}
+
+// Fields flag, add key=value field to config.
+type fieldsFlag struct {
+	config *Config
+}
+
+// Implement flag Value interface.
+func (f *fieldsFlag) String() string {
+	if f.config == nil || len(f.config.Fields) == 0 {
+		return ""
+	}
+
+	fields := make([]string, 0, len(f.config.Fields))
+	for k, v := range f.config.Fields {
+		fields = append(fields, fmt.Sprintf("%s=%v", k, v))
+	}
+	sort.Strings(fields)
+
+	return strings.Join(fields, ",")
+}
+
+// Implement flag Value interface.
+func (f *fieldsFlag) Set(value string) error {
+	n := strings.IndexByte(value, '=')
+	if n <= 0 {
+		return errors.New("field should be key=value format")
+	}
+
+	f.config.AddFields(value[:n], value[n+1:])
+
+	return nil
+}
+
+// Implement pflag Value interface.
+func (f *fieldsFlag) Type() string {
+	return "key=value"
+}
+
+// File flag, add lumberjack write to config.
+type fileFlag struct {
+	config *Config
+}
+
+// Implement flag Value interface.
+func (f *fileFlag) String() string {
+	if f.config == nil {
+		return ""
+	}
+
+	var files []string
+	for _, write := range f.config.Writes {
+		if l, ok := write.GetWriter().(*lumberjack.Logger); ok {
+			files = append(files, l.Filename)
+		}
+	}
+
+	return strings.Join(files, ",")
+}
+
+// Implement flag Value interface.
+func (f *fileFlag) Set(value string) error {
+	if value == "" {
+		return errors.New("file should not be empty")
+	}
+
+	f.config.AddSyncerWrite(&syncer.Write{
+		Name:   lumberjack.Name,
+		Config: lumberjack.New(value),
+	})
+
+	return nil
+}
+
+// Implement pflag Value interface.
+func (f *fileFlag) Type() string {
+	return "file"
+}
+
+// Config flag, load config file into config, the atomic level is kept in place.
+type configFlag struct {
+	config *Config
+	path   string
+}
+
+// Implement flag Value interface.
+func (f *configFlag) String() string {
+	return f.path
+}
+
+// Implement flag Value interface.
+func (f *configFlag) Set(value string) error {
+	config, err := LoadConfig(value)
+	if err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	// keep the atomic level in place.
+	if f.config.Level != (zap.AtomicLevel{}) {
+		f.config.Level.SetLevel(config.Level.Level())
+		config.Level = f.config.Level
+	}
+
+	*f.config = *config
+	f.path = value
+
+	return nil
+}
+
+// Implement pflag Value interface.
+func (f *configFlag) Type() string {
+	return "path"
 }
@@ -0,0 +1,49 @@
+package zap
+
+import (
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestConfig_RegisterFlagSet(t *testing.T) {
+	config := GetDefaultConfig()
+	level := config.Level
+
+	fs := config.NewFlagSet("test")
+
+	err := fs.Parse([]string{
+		"-log-config", "config.yaml",
+		"-log-level", "warn",
+		"-log-console=false",
+		"-log-fields", "app=test",
+		"-log-fields", "env=dev",
+		"-log-file", "flag.log",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("config", config)
+
+	if level.Level() != zap.WarnLevel || config.Level.Level() != zap.WarnLevel {
+		t.Fatalf("level flag should set atomic level in place: %s", config.Level)
+	}
+	if config.Console || !config.Development {
+		t.Fatalf("unexpected config: %s", config)
+	}
+	if config.Fields["app"] != "test" || config.Fields["env"] != "dev" {
+		t.Fatalf("unexpected fields: %v", config.Fields)
+	}
+
+	last := config.Writes[len(config.Writes)-1]
+	if l, ok := last.GetWriter().(*lumberjack.Logger); !ok || l.Filename != "flag.log" {
+		t.Fatalf("unexpected file write: %+v", last)
+	}
+
+	if err := fs.Parse([]string{"-log-level", "verbose"}); err == nil {
+		t.Fatal("unknown level should be rejected")
+	}
+}
@@ -2,21 +2,412 @@ package zap
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-framework/zap/collector"
 )
 
+// Convert proto level to zap level, return InvalidArgument error when level is unknown.
+func LevelToZap(level Level) (zapcore.Level, error) {
+	return collector.LevelToZap(level)
+}
+
+// Convert zap level to proto level, return InvalidArgument error when level is unknown.
+func LevelFromZap(level zapcore.Level) (Level, error) {
+	return collector.LevelFromZap(level)
+}
+
 // Implement LevelServiceServer interface.
 
 // Set logger atomic Level.
 func (c *Config) SetLevel(ctx context.Context, level *AtomicLevel) (*Empty, error) {
+	l, err := LevelToZap(level.Level)
+	if err != nil {
+		return nil, err
+	}
+
 	// set level.
-	c.Level.SetLevel(zapcore.Level(level.Level - 1))
-	return nil, nil
+	c.Level.SetLevel(l)
+
+	return &Empty{}, nil
 }
 
 // Get logger atomic Level.
 func (c *Config) GetLevel(context.Context, *Empty) (*AtomicLevel, error) {
 	// get level.
-	return &AtomicLevel{Level: Level(c.Level.Level() + 1)}, nil
+	level, err := LevelFromZap(c.Level.Level())
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &AtomicLevel{Level: level}, nil
+}
+
+// List the root logger and named loggers levels.
+func (c *Config) ListLoggers(context.Context, *Empty) (*Loggers, error) {
+	overrides := c.levelOverrides()
+
+	names := append([]string{""}, c.Levels.Names()...)
+
+	loggers := &Loggers{Loggers: make([]*LoggerLevel, 0, len(names))}
+	for _, name := range names {
+		l := c.Level.Level()
+		if name != "" {
+			var ok bool
+			if l, ok = c.Levels.Get(name); !ok {
+				continue
+			}
+		}
+
+		level, err := LevelFromZap(l)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		loggers.Loggers = append(loggers.Loggers, &LoggerLevel{
+			Name:     name,
+			Level:    level,
+			ExpireAt: overrides.expireAt(name),
+		})
+	}
+
+	return loggers, nil
+}
+
+// Set logger level by name, the level is reverted after ttl when ttl is positive.
+func (c *Config) SetLoggerLevel(ctx context.Context, req *SetLoggerLevelRequest) (*LoggerLevel, error) {
+	level, err := LevelToZap(req.Level)
+	if err != nil {
+		return nil, err
+	}
+	if req.TtlSeconds < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "negative ttl seconds %d", req.TtlSeconds)
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+
+	expire := c.levelOverrides().set(c, req.Name, level, ttl)
+
+	return &LoggerLevel{
+		Name:     req.Name,
+		Level:    req.Level,
+		ExpireAt: expire,
+	}, nil
+}
+
+// Unset named logger level, the logger inherits the parent level.
+func (c *Config) UnsetLoggerLevel(ctx context.Context, name *LoggerName) (*Empty, error) {
+	overrides := c.levelOverrides()
+
+	overrides.mutex.Lock()
+	overrides.cancel(name.Name)
+	overrides.mutex.Unlock()
+
+	if name.Name != "" {
+		c.Levels.Unset(name.Name)
+	}
+
+	return &Empty{}, nil
+}
+
+// get effective level of logger name, empty name is the root logger.
+func (c *Config) effectiveLevel(name string) zapcore.Level {
+	if name != "" {
+		if level, ok := c.Levels.Level(name); ok {
+			return level
+		}
+	}
+	return c.Level.Level()
+}
+
+// Level service v2 server of config.
+type levelServerV2 struct {
+	config *Config
+}
+
+// New level service v2 server of config.
+func NewLevelServerV2(config *Config) LevelServiceV2Server {
+	return &levelServerV2{config: config}
+}
+
+// Get effective level of logger name.
+func (s *levelServerV2) GetLevel(ctx context.Context, name *LoggerName) (*LoggerLevel, error) {
+	overrides := s.config.levelOverrides()
+
+	level, err := LevelFromZap(s.config.effectiveLevel(name.Name))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &LoggerLevel{
+		Name:     name.Name,
+		Level:    level,
+		ExpireAt: overrides.expireAt(name.Name),
+	}, nil
+}
+
+// Set logger level by name, the level is reverted after ttl when ttl is positive.
+func (s *levelServerV2) SetLevel(ctx context.Context, req *SetLoggerLevelRequest) (*LevelChange, error) {
+	previous, err := LevelFromZap(s.config.effectiveLevel(req.Name))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	level, err := s.config.SetLoggerLevel(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LevelChange{
+		Name:     level.Name,
+		Previous: previous,
+		Level:    level.Level,
+		ExpireAt: level.ExpireAt,
+	}, nil
+}
+
+// Unset named logger level, the root logger level can not be unset.
+func (s *levelServerV2) UnsetLevel(ctx context.Context, name *LoggerName) (*LevelChange, error) {
+	if name.Name == "" {
+		return nil, status.Error(codes.InvalidArgument, "root logger level can not be unset")
+	}
+
+	previous, err := LevelFromZap(s.config.effectiveLevel(name.Name))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if _, err := s.config.UnsetLoggerLevel(ctx, name); err != nil {
+		return nil, err
+	}
+
+	level, err := LevelFromZap(s.config.effectiveLevel(name.Name))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &LevelChange{
+		Name:     name.Name,
+		Previous: previous,
+		Level:    level,
+	}, nil
+}
+
+// List the root logger and named loggers levels.
+func (s *levelServerV2) ListLoggers(ctx context.Context, empty *Empty) (*Loggers, error) {
+	return s.config.ListLoggers(ctx, empty)
+}
+
+// List writers with their health.
+func (s *levelServerV2) ListWriters(ctx context.Context, empty *Empty) (*Writers, error) {
+	writers := s.config.writers()
+
+	states := make([]*WriterState, 0, len(writers))
+	for _, writer := range writers {
+		state := &WriterState{
+			Index:    int32(writer.Index),
+			Name:     writer.Name,
+			Encoding: writer.Encoding,
+			Healthy:  writer.Healthy,
+			Error:    writer.Error,
+		}
+		if writer.Level != nil {
+			level, err := LevelFromZap(*writer.Level)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			state.Level, state.HasLevel = level, true
+		}
+		if writer.MaxLevel != nil {
+			level, err := LevelFromZap(*writer.MaxLevel)
+			if err != nil {
+				return nil, status.Error(codes.Internal, err.Error())
+			}
+			state.MaxLevel, state.HasMaxLevel = level, true
+		}
+		states = append(states, state)
+	}
+
+	return &Writers{Writers: states}, nil
+}
+
+// Guard lazy initialization of level overrides.
+var levelOverridesMutex sync.Mutex
+
+// get level overrides of config.
+func (c *Config) levelOverrides() *levelOverrides {
+	levelOverridesMutex.Lock()
+	defer levelOverridesMutex.Unlock()
+
+	if c.overrides == nil {
+		c.overrides = &levelOverrides{items: make(map[string]*levelOverride)}
+	}
+	if c.Levels == nil {
+		c.Levels = NewLevelTree(nil)
+	}
+
+	return c.overrides
+}
+
+// Level override which is reverted when expired.
+type levelOverride struct {
+	// expire time.
+	expire time.Time
+	// revert timer.
+	timer *time.Timer
+	// override level.
+	level zapcore.Level
+	// level before override, nil means unset.
+	previous *zapcore.Level
+}
+
+// Level overrides with ttl by logger name.
+type levelOverrides struct {
+	mutex sync.Mutex
+	items map[string]*levelOverride
+}
+
+// get override expire time as unix seconds, zero is not overridden.
+func (o *levelOverrides) expireAt(name string) int64 {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if item, ok := o.items[name]; ok {
+		return item.expire.Unix()
+	}
+	return 0
+}
+
+// cancel override without revert, should be called with lock.
+func (o *levelOverrides) cancel(name string) *levelOverride {
+	item, ok := o.items[name]
+	if ok {
+		item.timer.Stop()
+		delete(o.items, name)
+	}
+	return item
+}
+
+// set level of name, revert it after ttl when ttl is positive, return expire time as unix seconds.
+func (o *levelOverrides) set(c *Config, name string, level zapcore.Level, ttl time.Duration) int64 {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// the previous level of an active override is the level before the first override.
+	previous := o.cancel(name)
+
+	if ttl > 0 {
+		item := &levelOverride{
+			expire: time.Now().Add(ttl),
+			level:  level,
+		}
+		if previous != nil {
+			item.previous = previous.previous
+		} else if name == "" {
+			l := c.Level.Level()
+			item.previous = &l
+		} else if l, ok := c.Levels.Get(name); ok {
+			item.previous = &l
+		}
+
+		item.timer = time.AfterFunc(ttl, func() {
+			o.revert(c, name, item)
+		})
+		o.items[name] = item
+	}
+
+	setNamedLevel(c, name, &level)
+
+	if item, ok := o.items[name]; ok {
+		return item.expire.Unix()
+	}
+	return 0
+}
+
+// revert expired override, the level changed after override is not reverted.
+func (o *levelOverrides) revert(c *Config, name string, item *levelOverride) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	// replaced by another override.
+	if o.items[name] != item {
+		return
+	}
+	delete(o.items, name)
+
+	if current := namedLevel(c, name); current == nil || *current != item.level {
+		return
+	}
+
+	setNamedLevel(c, name, item.previous)
+}
+
+// reset root level and named levels by reloaded config, the override is canceled when the reloaded
+// level of its name differs from the level before override, otherwise the override level is kept.
+func (o *levelOverrides) reset(c *Config, root zapcore.Level, levels map[string]zapcore.Level) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	for name, item := range o.items {
+		level := &root
+		if name != "" {
+			level = nil
+			if l, ok := levels[name]; ok {
+				level = &l
+			}
+		}
+
+		if !equalLevel(level, item.previous) {
+			o.cancel(name)
+			continue
+		}
+
+		if name == "" {
+			root = item.level
+		} else {
+			levels[name] = item.level
+		}
+	}
+
+	c.Level.SetLevel(root)
+	c.Levels.Reset(levels)
+}
+
+// compare levels, nil means unset.
+func equalLevel(a, b *zapcore.Level) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// get level of name, empty name is the root logger, nil means unset.
+func namedLevel(c *Config, name string) *zapcore.Level {
+	if name == "" {
+		level := c.Level.Level()
+		return &level
+	}
+	if level, ok := c.Levels.Get(name); ok {
+		return &level
+	}
+	return nil
+}
+
+// set level of name, empty name is the root logger, nil level unsets the named level.
+func setNamedLevel(c *Config, name string, level *zapcore.Level) {
+	switch {
+	case name == "":
+		if level != nil {
+			c.Level.SetLevel(*level)
+		}
+	case level == nil:
+		c.Levels.Unset(name)
+	default:
+		c.Levels.Set(name, *level)
+	}
 }
@@ -0,0 +1,312 @@
+package zap
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+// dial in-process grpc server with level and log services of config.
+func dialLevelServer(t *testing.T, config *Config) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+
+	server := grpc.NewServer()
+	RegisterLevelServiceServer(server, config)
+	RegisterLevelServiceV2Server(server, NewLevelServerV2(config))
+	RegisterLogServiceServer(server, config)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return listener.Dial()
+		}),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return conn
+}
+
+func TestLevelToZap(t *testing.T) {
+	tests := []struct {
+		level Level
+		zap   zapcore.Level
+		code  codes.Code
+	}{
+		{Level_Debug, zap.DebugLevel, codes.OK},
+		{Level_Info, zap.InfoLevel, codes.OK},
+		{Level_Warn, zap.WarnLevel, codes.OK},
+		{Level_Error, zap.ErrorLevel, codes.OK},
+		{Level_DPanic, zap.DPanicLevel, codes.OK},
+		{Level_Panic, zap.PanicLevel, codes.OK},
+		{Level_Fatal, zap.FatalLevel, codes.OK},
+		{Level(-1), zap.InfoLevel, codes.InvalidArgument},
+		{Level(7), zap.InfoLevel, codes.InvalidArgument},
+	}
+
+	for _, test := range tests {
+		level, err := LevelToZap(test.level)
+		if code := status.Code(err); code != test.code || level != test.zap {
+			t.Errorf("level %d expect %s %s, got %s %s", test.level, test.zap, test.code, level, code)
+		}
+		if err != nil {
+			continue
+		}
+		if l, err := LevelFromZap(level); err != nil || l != test.level {
+			t.Errorf("zap level %s expect %s, got %s %v", level, test.level, l, err)
+		}
+	}
+
+	if _, err := LevelFromZap(zapcore.Level(10)); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect invalid argument, got %v", err)
+	}
+}
+
+func TestLevelService(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	client := NewLevelServiceClient(dialLevelServer(t, config))
+
+	ctx := context.Background()
+
+	tests := []struct {
+		level Level
+		code  codes.Code
+		want  zapcore.Level
+	}{
+		{Level_Debug, codes.OK, zap.DebugLevel},
+		{Level_Error, codes.OK, zap.ErrorLevel},
+		{Level(9), codes.InvalidArgument, zap.ErrorLevel},
+		{Level(-1), codes.InvalidArgument, zap.ErrorLevel},
+		{Level_Info, codes.OK, zap.InfoLevel},
+	}
+
+	for _, test := range tests {
+		empty, err := client.SetLevel(ctx, &AtomicLevel{Level: test.level})
+		if code := status.Code(err); code != test.code {
+			t.Errorf("set level %d expect %s, got %v", test.level, test.code, err)
+		}
+		if err == nil && empty == nil {
+			t.Errorf("set level %d expect empty response", test.level)
+		}
+		if config.Level.Level() != test.want {
+			t.Errorf("set level %d expect %s, got %s", test.level, test.want, config.Level.Level())
+		}
+
+		level, err := client.GetLevel(ctx, &Empty{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if l, _ := LevelFromZap(test.want); level.Level != l {
+			t.Errorf("get level expect %s, got %s", l, level.Level)
+		}
+	}
+}
+
+func TestLevelServiceV2(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	client := NewLevelServiceV2Client(dialLevelServer(t, config))
+
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		request  *SetLoggerLevelRequest
+		code     codes.Code
+		previous Level
+		level    Level
+		expire   bool
+	}{
+		{"root", &SetLoggerLevelRequest{Level: Level_Warn}, codes.OK, Level_Info, Level_Warn, false},
+		{"inherit root", &SetLoggerLevelRequest{Name: "db", Level: Level_Debug}, codes.OK, Level_Warn, Level_Debug, false},
+		{"inherit parent", &SetLoggerLevelRequest{Name: "db.sql", Level: Level_Error, TtlSeconds: 60}, codes.OK, Level_Debug, Level_Error, true},
+		{"override", &SetLoggerLevelRequest{Name: "db.sql", Level: Level_Info, TtlSeconds: 60}, codes.OK, Level_Error, Level_Info, true},
+		{"unknown level", &SetLoggerLevelRequest{Name: "db", Level: Level(7)}, codes.InvalidArgument, 0, 0, false},
+		{"negative ttl", &SetLoggerLevelRequest{Name: "db", Level: Level_Info, TtlSeconds: -1}, codes.InvalidArgument, 0, 0, false},
+	}
+
+	for _, test := range tests {
+		change, err := client.SetLevel(ctx, test.request)
+		if code := status.Code(err); code != test.code {
+			t.Errorf("%s: expect %s, got %v", test.name, test.code, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if change.Previous != test.previous || change.Level != test.level || (change.ExpireAt != 0) != test.expire {
+			t.Errorf("%s: unexpected change %v", test.name, change)
+		}
+
+		level, err := client.GetLevel(ctx, &LoggerName{Name: test.request.Name})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if level.Level != test.level {
+			t.Errorf("%s: expect level %s, got %s", test.name, test.level, level.Level)
+		}
+	}
+
+	// unset falls back to the parent level.
+	change, err := client.UnsetLevel(ctx, &LoggerName{Name: "db.sql"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change.Previous != Level_Info || change.Level != Level_Debug {
+		t.Errorf("unexpected unset change %v", change)
+	}
+
+	// root logger level can not be unset.
+	if _, err := client.UnsetLevel(ctx, &LoggerName{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expect invalid argument, got %v", err)
+	}
+
+	loggers, err := client.ListLoggers(ctx, &Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loggers.Loggers) != 2 || loggers.Loggers[0].Level != Level_Warn || loggers.Loggers[1].Name != "db" {
+		t.Errorf("unexpected loggers %v", loggers.Loggers)
+	}
+}
+
+func TestLevelServiceV2_ListWriters(t *testing.T) {
+	config := GetDefaultConfig().
+		AddSyncerWrite((&syncer.Write{Name: "unknown"}).SetLevel(zap.WarnLevel))
+	client := NewLevelServiceV2Client(dialLevelServer(t, config))
+
+	writers, err := client.ListWriters(context.Background(), &Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(writers.Writers) != 1 {
+		t.Fatalf("unexpected writers %v", writers.Writers)
+	}
+
+	writer := writers.Writers[0]
+	if writer.Name != "unknown" || !writer.HasLevel || writer.Level != Level_Warn || writer.HasMaxLevel || writer.Healthy || writer.Error == "" {
+		t.Errorf("unexpected writer %v", writer)
+	}
+}
+
+func TestConfig_SetLoggerLevel(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.SetNamedLevel("db", zap.WarnLevel)
+
+	ctx := context.Background()
+
+	// permanent named level.
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "http", Level: Level_Error}); err != nil {
+		t.Fatal(err)
+	}
+	if level, ok := config.Levels.Get("http"); !ok || level != zap.ErrorLevel {
+		t.Fatalf("expect http level error, got %s %t", level, ok)
+	}
+
+	// temporary named level reverts to the previous level.
+	resp, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "db", Level: Level_Debug, TtlSeconds: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ExpireAt == 0 {
+		t.Fatal("expect expire at")
+	}
+	// replace the override, previous level is kept.
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "db", Level: Level_Info, TtlSeconds: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// temporary root level.
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Level: Level_Debug, TtlSeconds: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if config.Level.Level() != zap.DebugLevel {
+		t.Fatalf("expect root level debug, got %s", config.Level.Level())
+	}
+
+	// temporary new name is unset after ttl.
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "audit", Level: Level_Debug, TtlSeconds: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	loggers, err := config.ListLoggers(ctx, &Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loggers.Loggers) != 4 || loggers.Loggers[0].Name != "" || loggers.Loggers[0].ExpireAt == 0 {
+		t.Fatalf("unexpected loggers %v", loggers.Loggers)
+	}
+	t.Log(loggers.Loggers)
+
+	// the level changed after override is not reverted.
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "cache", Level: Level_Debug, TtlSeconds: 1}); err != nil {
+		t.Fatal(err)
+	}
+	config.Levels.Set("cache", zap.ErrorLevel)
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if level, ok := config.Levels.Get("cache"); !ok || level != zap.ErrorLevel {
+		t.Errorf("expect cache level kept error, got %s %t", level, ok)
+	}
+
+	if level, ok := config.Levels.Get("db"); !ok || level != zap.WarnLevel {
+		t.Errorf("expect db level reverted to warn, got %s %t", level, ok)
+	}
+	if _, ok := config.Levels.Get("audit"); ok {
+		t.Error("expect audit level unset")
+	}
+	if config.Level.Level() != zap.InfoLevel {
+		t.Errorf("expect root level reverted to info, got %s", config.Level.Level())
+	}
+
+	loggers, err = config.ListLoggers(ctx, &Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, logger := range loggers.Loggers {
+		if logger.ExpireAt != 0 {
+			t.Errorf("expect no active override of %q", logger.Name)
+		}
+	}
+}
+
+func TestConfig_UnsetLoggerLevel(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+
+	ctx := context.Background()
+
+	if _, err := config.SetLoggerLevel(ctx, &SetLoggerLevelRequest{Name: "db", Level: Level_Debug, TtlSeconds: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.UnsetLoggerLevel(ctx, &LoggerName{Name: "db"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.Levels.Get("db"); ok {
+		t.Fatal("expect db level unset")
+	}
+
+	// the cancelled override does not revert.
+	config.SetNamedLevel("db", zapcore.ErrorLevel)
+	time.Sleep(1500 * time.Millisecond)
+
+	if level, ok := config.Levels.Get("db"); !ok || level != zap.ErrorLevel {
+		t.Errorf("expect db level error, got %s %t", level, ok)
+	}
+}
@@ -0,0 +1,255 @@
+package zap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+// Http admin handler of config.
+//
+//	GET    /level           get the root logger level, as zap.AtomicLevel.ServeHTTP.
+//	PUT    /level           set the root logger level, body is {"level":"debug"}.
+//	GET    /loggers         list the root logger and named loggers levels.
+//	GET    /loggers/{name}  get effective level of logger name.
+//	PUT    /loggers/{name}  set logger level by name, body is {"level":"debug","ttl":"10m"}.
+//	DELETE /loggers/{name}  unset named logger level.
+//	GET    /config          get the effective config, the writer secrets are redacted by syncer.Redactor,
+//	                        e.g. the http password and headers and the websocket url credentials.
+//	GET    /writers         list the writers with their health.
+//
+// Mount the handler on a sub path with http.StripPrefix.
+func (c *Config) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("/level", c.Level)
+	mux.HandleFunc("/loggers", c.serveLoggers)
+	mux.HandleFunc("/loggers/", c.serveLogger)
+	mux.HandleFunc("/config", c.serveConfig)
+	mux.HandleFunc("/writers", c.serveWriters)
+
+	return mux
+}
+
+// Http admin handler of the current config, the config is resolved at each request,
+// so the reloaded config and its writers are served, see Config Handler.
+func (w *ConfigWatcher) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		w.Config().Handler().ServeHTTP(rw, r)
+	})
+}
+
+// Http logger level.
+type httpLoggerLevel struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string `json:"name"`
+	// Logger level.
+	Level zapcore.Level `json:"level"`
+	// Override expire time, omitted when never expire.
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+}
+
+// Http set logger level request.
+type httpSetLevelRequest struct {
+	// Logger level.
+	Level *zapcore.Level `json:"level"`
+	// Override time to live, the level is reverted when expired, empty is permanent.
+	TTL string `json:"ttl"`
+}
+
+// Http writer state.
+type httpWriter struct {
+	// Write index.
+	Index int `json:"index"`
+	// Write name.
+	Name string `json:"name"`
+	// Write minimum enabled level.
+	Level *zapcore.Level `json:"level,omitempty"`
+	// Write maximum enabled level.
+	MaxLevel *zapcore.Level `json:"max_level,omitempty"`
+	// Write encoding.
+	Encoding string `json:"encoding,omitempty"`
+	// Write is healthy.
+	Healthy bool `json:"healthy"`
+	// Health error.
+	Error string `json:"error,omitempty"`
+}
+
+// write json response.
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = jsoniter.NewEncoder(w).Encode(v)
+}
+
+// write json error response.
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
+
+// get logger level of name.
+func (c *Config) loggerLevel(name string, level zapcore.Level) httpLoggerLevel {
+	l := httpLoggerLevel{Name: name, Level: level}
+	if expire := c.levelOverrides().expireAt(name); expire != 0 {
+		t := time.Unix(expire, 0)
+		l.ExpireAt = &t
+	}
+	return l
+}
+
+// serve logger levels list.
+func (c *Config) serveLoggers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	c.levelOverrides()
+
+	loggers := []httpLoggerLevel{c.loggerLevel("", c.Level.Level())}
+	for _, name := range c.Levels.Names() {
+		if level, ok := c.Levels.Get(name); ok {
+			loggers = append(loggers, c.loggerLevel(name, level))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, loggers)
+}
+
+// serve named logger level.
+func (c *Config) serveLogger(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/loggers/")
+
+	switch r.Method {
+	case http.MethodGet:
+		c.levelOverrides()
+		writeJSON(w, http.StatusOK, c.loggerLevel(name, c.effectiveLevel(name)))
+
+	case http.MethodPut:
+		var req httpSetLevelRequest
+		if err := jsoniter.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if req.Level == nil {
+			writeError(w, http.StatusBadRequest, errors.New("level: must not be empty"))
+			return
+		}
+
+		var ttl time.Duration
+		if req.TTL != "" {
+			var err error
+			if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl < 0 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("ttl: invalid duration %q", req.TTL))
+				return
+			}
+		}
+
+		c.levelOverrides().set(c, name, *req.Level, ttl)
+
+		writeJSON(w, http.StatusOK, c.loggerLevel(name, *req.Level))
+
+	case http.MethodDelete:
+		if name == "" {
+			writeError(w, http.StatusBadRequest, errors.New("root logger level can not be unset"))
+			return
+		}
+
+		_, _ = c.UnsetLoggerLevel(r.Context(), &LoggerName{Name: name})
+
+		writeJSON(w, http.StatusOK, c.loggerLevel(name, c.effectiveLevel(name)))
+
+	default:
+		w.Header().Set("Allow", "GET, PUT, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+	}
+}
+
+// serve effective config.
+func (c *Config) serveConfig(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintln(w, c.redacted().String())
+}
+
+// Redacted writer marshals the redacted view instead of the writer config.
+type redactedWriter struct {
+	io.Writer
+	view interface{}
+}
+
+// Implement json Marshaler.
+func (w *redactedWriter) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(w.view)
+}
+
+// get config view with the writer configs redacted by syncer.Redactor.
+func (c *Config) redacted() *Config {
+	config := *c
+
+	config.Writes = make([]*syncer.Write, 0, len(c.Writes))
+	for _, write := range c.Writes {
+		if write == nil {
+			config.Writes = append(config.Writes, write)
+			continue
+		}
+		if redactor, ok := write.GetWriter().(syncer.Redactor); ok {
+			w := *write
+			w.Config = &redactedWriter{Writer: write.Config, view: redactor.Redact()}
+			write = &w
+		}
+		config.Writes = append(config.Writes, write)
+	}
+
+	return &config
+}
+
+// serve writers with their health.
+func (c *Config) serveWriters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s is not allowed", r.Method))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, c.writers())
+}
+
+// get writers with their health.
+func (c *Config) writers() []httpWriter {
+	writers := make([]httpWriter, 0, len(c.Writes))
+	for i, write := range c.Writes {
+		if write == nil {
+			continue
+		}
+		writer := httpWriter{
+			Index:    i,
+			Name:     write.Name,
+			Level:    write.Level,
+			MaxLevel: write.MaxLevel,
+			Encoding: write.Encoding,
+			Healthy:  true,
+		}
+		if err := write.Health(); err != nil {
+			writer.Healthy = false
+			writer.Error = err.Error()
+		}
+		writers = append(writers, writer)
+	}
+
+	return writers
+}
@@ -0,0 +1,228 @@
+package zap
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+
+	"github.com/go-framework/zap/syncer"
+	httpsyncer "github.com/go-framework/zap/syncer/http"
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestConfig_Handler(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: lumberjack.Name, Config: lumberjack.New("/not/exist/dir/app.log")})
+
+	server := httptest.NewServer(config.Handler())
+	defer server.Close()
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		code   int
+		expect string
+	}{
+		{http.MethodGet, "/level", "", http.StatusOK, `{"level":"info"}`},
+		{http.MethodPut, "/level", `{"level":"warn"}`, http.StatusOK, `{"level":"warn"}`},
+		{http.MethodPut, "/loggers/db", `{"level":"debug"}`, http.StatusOK, `{"name":"db","level":"debug"}`},
+		{http.MethodPut, "/loggers/http", `{"level":"error","ttl":"10m"}`, http.StatusOK, `"expire_at":`},
+		{http.MethodPut, "/loggers/http", `{"level":"error","ttl":"ten"}`, http.StatusBadRequest, `ttl: invalid duration`},
+		{http.MethodPut, "/loggers/http", `{"level":"verbose"}`, http.StatusBadRequest, `error`},
+		{http.MethodPut, "/loggers/http", `{}`, http.StatusBadRequest, `level: must not be empty`},
+		{http.MethodGet, "/loggers/db.sql", "", http.StatusOK, `{"name":"db.sql","level":"debug"}`},
+		{http.MethodGet, "/loggers", "", http.StatusOK, `[{"name":"","level":"warn"},{"name":"db","level":"debug"},{"name":"http","level":"error","expire_at":`},
+		{http.MethodDelete, "/loggers/db", "", http.StatusOK, `{"name":"db","level":"warn"}`},
+		{http.MethodDelete, "/loggers/", "", http.StatusBadRequest, `root logger level can not be unset`},
+		{http.MethodPost, "/loggers", "", http.StatusMethodNotAllowed, `not allowed`},
+		{http.MethodGet, "/config", "", http.StatusOK, `"level":"warn"`},
+		{http.MethodGet, "/writers", "", http.StatusOK, `[{"index":0,"name":"lumberjack","healthy":false,"error":`},
+	}
+
+	for _, test := range tests {
+		req, err := http.NewRequest(test.method, server.URL+test.path, strings.NewReader(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != test.code || !strings.Contains(string(data), test.expect) {
+			t.Errorf("%s %s expect %d %s, got %d %s", test.method, test.path, test.code, test.expect, resp.StatusCode, data)
+		}
+	}
+
+	if _, ok := config.Levels.Get("db"); ok {
+		t.Error("expect db level unset")
+	}
+
+	// config round trip.
+	resp, err := http.Get(server.URL + "/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	c := &Config{}
+	if err := jsoniter.NewDecoder(resp.Body).Decode(c); err != nil {
+		t.Fatal(err)
+	}
+	if c.Level.Level() != zap.WarnLevel {
+		t.Errorf("expect config level warn, got %s", c.Level.Level())
+	}
+}
+
+// Writer with secret config.
+type secretWriter struct {
+	URL      string `json:"url"`
+	Password string `json:"password"`
+}
+
+// Implement Writer interface.
+func (w *secretWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+// Implement Redactor interface.
+func (w *secretWriter) Redact() interface{} {
+	view := *w
+	view.Password = syncer.Redacted
+	return &view
+}
+
+func TestConfig_HandlerRedacted(t *testing.T) {
+	writer := &secretWriter{URL: "http://localhost", Password: "s3cret"}
+
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: "secret", Config: writer})
+
+	server := httptest.NewServer(config.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(data), "s3cret") {
+		t.Fatalf("secret should not be served: %s", data)
+	}
+	if !strings.Contains(string(data), `"config":{"url":"http://localhost","password":"******"}`) {
+		t.Errorf("redacted config should be served: %s", data)
+	}
+	if writer.Password != "s3cret" || config.Writes[0].GetWriter() != writer {
+		t.Error("writer config should not be changed")
+	}
+}
+
+// get response body of url.
+func getBody(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestConfig_HandlerWriters(t *testing.T) {
+	writer := httpsyncer.New(httpsyncer.Loki, "http://localhost:3100")
+	writer.Headers = map[string]string{"X-Scope-OrgID": "tenant-secret"}
+	writer.Password = "s3cret"
+
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: "http", Config: writer})
+	config.Writes = append(config.Writes, nil)
+
+	server := httptest.NewServer(config.Handler())
+	defer server.Close()
+
+	// the http writer secrets are redacted.
+	if data := getBody(t, server.URL+"/config"); strings.Contains(data, "secret") || !strings.Contains(data, `"password":"******"`) {
+		t.Errorf("redacted config should be served: %s", data)
+	}
+
+	// the nil write is skipped.
+	var writers []httpWriter
+	if err := jsoniter.UnmarshalFromString(getBody(t, server.URL+"/writers"), &writers); err != nil {
+		t.Fatal(err)
+	}
+	if len(writers) != 1 || writers[0].Name != "http" {
+		t.Errorf("unexpected writers %+v", writers)
+	}
+}
+
+func TestConfigWatcher_Handler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	writeFile(t, filename, `
+writes:
+  - name: lumberjack
+    config:
+      filename: `+filepath.Join(dir, "a.log"))
+
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	server := httptest.NewServer(w.Handler())
+	defer server.Close()
+
+	writeFile(t, filename, `
+writes:
+  - name: lumberjack
+    config:
+      filename: `+filepath.Join(dir, "a.log")+`
+  - name: lumberjack
+    level: error
+    config:
+      filename: `+filepath.Join(dir, "b.log"))
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the reloaded writers are served.
+	var writers []httpWriter
+	if err := jsoniter.UnmarshalFromString(getBody(t, server.URL+"/writers"), &writers); err != nil {
+		t.Fatal(err)
+	}
+	if len(writers) != 2 || !writers[0].Healthy || !writers[1].Healthy {
+		t.Errorf("unexpected writers %+v", writers)
+	}
+	if data := getBody(t, server.URL+"/config"); !strings.Contains(data, "b.log") {
+		t.Errorf("reloaded config should be served: %s", data)
+	}
+}
@@ -0,0 +1,261 @@
+package zap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Level tree, levels are keyed by logger name prefix, the longest matched prefix wins,
+// prefix is matched by name segments, "http" matches "http" and "http.client" but not "https".
+// Level tree is safe for concurrent use and can be adjusted at runtime.
+type LevelTree struct {
+	// levels snapshot as map[string]zapcore.Level.
+	levels atomic.Value
+	mutex  sync.Mutex
+}
+
+// New level tree.
+func NewLevelTree(levels map[string]zapcore.Level) *LevelTree {
+	t := &LevelTree{}
+	t.Reset(levels)
+	return t
+}
+
+// get levels snapshot.
+func (t *LevelTree) load() map[string]zapcore.Level {
+	if t == nil {
+		return nil
+	}
+	levels, _ := t.levels.Load().(map[string]zapcore.Level)
+	return levels
+}
+
+// update levels snapshot by copy on write.
+func (t *LevelTree) update(fn func(levels map[string]zapcore.Level)) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	old := t.load()
+	levels := make(map[string]zapcore.Level, len(old)+1)
+	for name, level := range old {
+		levels[name] = level
+	}
+
+	fn(levels)
+
+	t.levels.Store(levels)
+}
+
+// Reset all levels.
+func (t *LevelTree) Reset(levels map[string]zapcore.Level) {
+	t.update(func(m map[string]zapcore.Level) {
+		for name := range m {
+			delete(m, name)
+		}
+		for name, level := range levels {
+			m[name] = level
+		}
+	})
+}
+
+// Set level of logger name prefix.
+func (t *LevelTree) Set(name string, level zapcore.Level) {
+	t.update(func(levels map[string]zapcore.Level) {
+		levels[name] = level
+	})
+}
+
+// Unset level of logger name prefix.
+func (t *LevelTree) Unset(name string) {
+	t.update(func(levels map[string]zapcore.Level) {
+		delete(levels, name)
+	})
+}
+
+// Get level of logger name prefix.
+func (t *LevelTree) Get(name string) (zapcore.Level, bool) {
+	level, ok := t.load()[name]
+	return level, ok
+}
+
+// Get effective level of logger name by the longest matched prefix.
+func (t *LevelTree) Level(name string) (zapcore.Level, bool) {
+	levels := t.load()
+	if len(levels) == 0 {
+		return zapcore.InfoLevel, false
+	}
+
+	for {
+		if level, ok := levels[name]; ok {
+			return level, true
+		}
+		n := strings.LastIndexByte(name, '.')
+		if n < 0 {
+			return zapcore.InfoLevel, false
+		}
+		name = name[:n]
+	}
+}
+
+// Get min level of tree.
+func (t *LevelTree) MinLevel() (zapcore.Level, bool) {
+	levels := t.load()
+	if len(levels) == 0 {
+		return zapcore.InfoLevel, false
+	}
+
+	min := zapcore.FatalLevel
+	for _, level := range levels {
+		if level < min {
+			min = level
+		}
+	}
+
+	return min, true
+}
+
+// Get a copy of levels.
+func (t *LevelTree) Levels() map[string]zapcore.Level {
+	old := t.load()
+	levels := make(map[string]zapcore.Level, len(old))
+	for name, level := range old {
+		levels[name] = level
+	}
+	return levels
+}
+
+// Get sorted logger name prefixes.
+func (t *LevelTree) Names() []string {
+	levels := t.load()
+	names := make([]string, 0, len(levels))
+	for name := range levels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Clone level tree.
+func (t *LevelTree) Clone() *LevelTree {
+	if t == nil {
+		return nil
+	}
+	return NewLevelTree(t.load())
+}
+
+// Implement Stringer.
+func (t *LevelTree) String() string {
+	levels := t.load()
+
+	items := make([]string, 0, len(levels))
+	for _, name := range t.Names() {
+		items = append(items, fmt.Sprintf("%s=%s", name, levels[name]))
+	}
+
+	return strings.Join(items, ",")
+}
+
+// get text levels.
+func (t *LevelTree) text() map[string]string {
+	levels := t.load()
+	m := make(map[string]string, len(levels))
+	for name, level := range levels {
+		m[name] = level.String()
+	}
+	return m
+}
+
+// parse text levels.
+func (t *LevelTree) parse(m map[string]string) error {
+	levels := make(map[string]zapcore.Level, len(m))
+	for name, text := range m {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("levels.%s: %v", name, err)
+		}
+		levels[name] = level
+	}
+
+	t.Reset(levels)
+
+	return nil
+}
+
+// Implement JSON Marshaler interface.
+func (t *LevelTree) MarshalJSON() ([]byte, error) {
+	return jsoniter.Marshal(t.text())
+}
+
+// Implement JSON Unmarshaler interface.
+func (t *LevelTree) UnmarshalJSON(data []byte) error {
+	m := make(map[string]string)
+	if err := jsoniter.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	return t.parse(m)
+}
+
+// Implement YAML Marshaler interface.
+func (t *LevelTree) MarshalYAML() (interface{}, error) {
+	return t.text(), nil
+}
+
+// Implement YAML Unmarshaler interface.
+func (t *LevelTree) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	m := make(map[string]string)
+	if err := unmarshal(&m); err != nil {
+		return err
+	}
+	return t.parse(m)
+}
+
+// Level core, enforces the level tree by logger name and the atomic level for others,
+// the wrapped core should not check the atomic level again.
+type levelCore struct {
+	zapcore.Core
+	level zap.AtomicLevel
+	tree  *LevelTree
+}
+
+// New level core.
+func newLevelCore(core zapcore.Core, level zap.AtomicLevel, tree *LevelTree) zapcore.Core {
+	return &levelCore{
+		Core:  core,
+		level: level,
+		tree:  tree,
+	}
+}
+
+// Implement zapcore LevelEnabler interface, enabled when any logger name may be enabled.
+func (l *levelCore) Enabled(level zapcore.Level) bool {
+	if l.level.Enabled(level) {
+		return true
+	}
+	min, ok := l.tree.MinLevel()
+	return ok && level >= min
+}
+
+// Implement zapcore Core interface.
+func (l *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return newLevelCore(l.Core.With(fields), l.level, l.tree)
+}
+
+// Implement zapcore Core interface.
+func (l *levelCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if level, ok := l.tree.Level(entry.LoggerName); ok {
+		if entry.Level < level {
+			return ce
+		}
+	} else if !l.level.Enabled(entry.Level) {
+		return ce
+	}
+
+	return l.Core.Check(entry, ce)
+}
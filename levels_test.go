@@ -0,0 +1,88 @@
+package zap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+func TestLevelTree_Level(t *testing.T) {
+	tree := NewLevelTree(map[string]zapcore.Level{
+		"db":          zap.DebugLevel,
+		"http.client": zap.WarnLevel,
+	})
+
+	tests := []struct {
+		name  string
+		level zapcore.Level
+		ok    bool
+	}{
+		{"db", zap.DebugLevel, true},
+		{"db.sql", zap.DebugLevel, true},
+		{"dbx", zap.InfoLevel, false},
+		{"http", zap.InfoLevel, false},
+		{"http.client", zap.WarnLevel, true},
+		{"http.client.retry", zap.WarnLevel, true},
+		{"", zap.InfoLevel, false},
+	}
+
+	for _, test := range tests {
+		if level, ok := tree.Level(test.name); level != test.level || ok != test.ok {
+			t.Errorf("name %q expect %s %t, got %s %t", test.name, test.level, test.ok, level, ok)
+		}
+	}
+
+	if min, ok := tree.MinLevel(); !ok || min != zap.DebugLevel {
+		t.Errorf("unexpected min level %s", min)
+	}
+}
+
+func TestConfig_Levels(t *testing.T) {
+	config := &Config{}
+	err := yaml.Unmarshal([]byte(`
+level: info
+levels:
+  db: debug
+  http.client: warn
+`), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("config", config)
+
+	buf := &bytes.Buffer{}
+	config.AddSyncerWrite(&syncer.Write{Name: "buffer", Config: buf})
+
+	logger := config.NewZapLogger()
+
+	logger.Named("db").Named("sql").Debug("db debug")
+	logger.Named("dbx").Debug("dbx debug")
+	logger.Named("http").Info("http info")
+	logger.Named("http.client").Info("client info")
+	logger.Debug("root debug")
+
+	// runtime adjustment.
+	config.SetNamedLevel("http", zap.ErrorLevel)
+	logger.Named("http").Warn("http warn")
+	config.Levels.Unset("db")
+	logger.Named("db").Debug("db debug after unset")
+
+	out := buf.String()
+	for _, expect := range []string{"db debug", "http info"} {
+		if !strings.Contains(out, expect) {
+			t.Errorf("%q should be logged", expect)
+		}
+	}
+	for _, unexpect := range []string{"dbx debug", "client info", "root debug", "http warn", "db debug after unset"} {
+		if strings.Contains(out, unexpect) {
+			t.Errorf("%q should not be logged", unexpect)
+		}
+	}
+}
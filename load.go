@@ -0,0 +1,77 @@
+package zap
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+)
+
+// Parse config data, the data format is json when the path extension is .json, otherwise is yaml.
+// The environment variable overrides are applied, see ApplyEnv.
+func ParseConfig(path string, data []byte) (*Config, error) {
+	config := &Config{}
+
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = jsoniter.Unmarshal(data, config)
+	default:
+		err = yaml.Unmarshal(data, config)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// level default is info.
+	if config.Level == (zap.AtomicLevel{}) {
+		config.Level = zap.NewAtomicLevel()
+	}
+
+	if err := config.ApplyEnv(); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Load config from yaml or json file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseConfig(path, data)
+}
+
+// Parse registry data, the data format is json when the path extension is .json, otherwise is yaml.
+func ParseRegistry(path string, data []byte) (*Registry, error) {
+	registry := NewRegistry()
+
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = jsoniter.Unmarshal(data, registry)
+	default:
+		err = yaml.Unmarshal(data, registry)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// Load registry from yaml or json file.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseRegistry(path, data)
+}
@@ -10,6 +10,9 @@ import (
 // default zap logger.
 var defaultLogger = zapConfig.NewZapLogger(zapConfig.GetDebugConfig(), zap.AddCallerSkip(1))
 
+// named loggers registry.
+var registry *zapConfig.Registry
+
 // Set zap logger.
 func Set(logger *zap.Logger) {
 	if logger == nil {
@@ -22,6 +25,16 @@ func Get() *zap.Logger {
 	return defaultLogger
 }
 
+// Set named loggers registry, Named routes the declared names to the registry loggers.
+func SetRegistry(r *zapConfig.Registry) {
+	registry = r
+}
+
+// Get named loggers registry.
+func GetRegistry() *zapConfig.Registry {
+	return registry
+}
+
 // Sugar wraps the Logger to provide a more ergonomic, but slightly slower,
 // API. Sugaring a Logger is quite inexpensive, so it's reasonable for a
 // single application to use both Loggers and SugaredLoggers, converting
@@ -31,8 +44,12 @@ func Sugar() *zap.SugaredLogger {
 }
 
 // Named adds a new path segment to the logger's name. Segments are joined by
-// periods. By default, Loggers are unnamed.
+// periods. By default, Loggers are unnamed. When the name is declared in the
+// registry, the registry logger is returned.
 func Named(s string) *zap.Logger {
+	if registry != nil && registry.Has(s) {
+		return registry.GetNamed(s)
+	}
 	return defaultLogger.Named(s)
 }
 
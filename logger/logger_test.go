@@ -2,12 +2,27 @@ package logger
 
 import (
 	"testing"
+
+	zapConfig "github.com/go-framework/zap"
 )
 
 func TestInfo(t *testing.T) {
 	Info("info")
 }
 
+func TestNamed(t *testing.T) {
+	registry := zapConfig.NewRegistry().Set("db", zapConfig.GetDebugConfig())
+
+	SetRegistry(registry)
+	defer SetRegistry(nil)
+
+	if Named("db") != registry.GetNamed("db") {
+		t.Fatal("declared name should be routed to registry")
+	}
+
+	Named("http").Info("named")
+}
+
 func TestFatal(t *testing.T) {
 	Fatal("fatal")
 }
@@ -0,0 +1,417 @@
+package zap
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+const (
+	// Default logger name, the default entry is inherited by other loggers.
+	DefaultLoggerName = "default"
+)
+
+var (
+	// Default registry.
+	DefaultRegistry = NewRegistry()
+)
+
+// Get named logger from default registry.
+func GetNamed(name string) *zap.Logger {
+	return DefaultRegistry.GetNamed(name)
+}
+
+// Named loggers registry, the loggers are built lazily and cached.
+//
+// Loggers are declared as:
+//
+//	loggers:
+//	  default:
+//	    level: info
+//	    console: true
+//	  audit:
+//	    console: false
+//	    writes:
+//	      - name: lumberjack
+//	        config:
+//	          filename: audit.log
+//
+// every entry inherits the default entry, maps are merged deeply and other values are replaced.
+type Registry struct {
+	// Logger configs by name.
+	Loggers map[string]*Config `json:"loggers" yaml:"loggers"`
+
+	// logger options.
+	opts []zap.Option
+	// built loggers.
+	loggers map[string]*zap.Logger
+	mutex   sync.RWMutex
+}
+
+// New registry with logger options.
+func NewRegistry(opts ...zap.Option) *Registry {
+	return &Registry{
+		Loggers: make(map[string]*Config),
+		opts:    opts,
+	}
+}
+
+// Implement Stringer.
+func (r *Registry) String() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	data, err := jsoniter.MarshalToString(r.Loggers)
+	if err != nil {
+		return fmt.Sprintf("loggers: %v", r.names())
+	}
+	return data
+}
+
+// Set logger options, only effect loggers built later.
+func (r *Registry) SetOptions(opts ...zap.Option) *Registry {
+	r.mutex.Lock()
+	r.opts = opts
+	r.mutex.Unlock()
+	return r
+}
+
+// Set named logger config, the cached logger is rebuilt on next get.
+func (r *Registry) Set(name string, config *Config) *Registry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.Loggers == nil {
+		r.Loggers = make(map[string]*Config)
+	}
+	r.Loggers[name] = config
+
+	if name == DefaultLoggerName {
+		r.loggers = nil
+	} else {
+		delete(r.loggers, name)
+	}
+
+	return r
+}
+
+// Has named logger config.
+func (r *Registry) Has(name string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, ok := r.Loggers[name]
+	return ok
+}
+
+// Get named logger config, fallback to default config.
+func (r *Registry) Config(name string) *Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.config(name)
+}
+
+// get named logger config without lock.
+func (r *Registry) config(name string) *Config {
+	if config, ok := r.Loggers[name]; ok {
+		return config
+	}
+	if config, ok := r.Loggers[DefaultLoggerName]; ok {
+		return config
+	}
+	return DefaultZapConfig
+}
+
+// Get sorted logger names.
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.names()
+}
+
+// get sorted logger names without lock.
+func (r *Registry) names() []string {
+	names := make([]string, 0, len(r.Loggers))
+	for name := range r.Loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get named logger, the logger is built from the named config when declared,
+// otherwise it is the default logger named as name.
+func (r *Registry) GetNamed(name string) *zap.Logger {
+	r.mutex.RLock()
+	logger, ok := r.loggers[name]
+	r.mutex.RUnlock()
+	if ok {
+		return logger
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.getNamed(name)
+}
+
+// get named logger without lock.
+func (r *Registry) getNamed(name string) *zap.Logger {
+	if logger, ok := r.loggers[name]; ok {
+		return logger
+	}
+
+	var logger *zap.Logger
+
+	switch _, ok := r.Loggers[name]; {
+	case name == DefaultLoggerName:
+		logger = r.config(name).NewZapLogger(r.opts...)
+	case ok:
+		logger = r.config(name).NewZapLogger(r.opts...).Named(name)
+	default:
+		logger = r.getNamed(DefaultLoggerName).Named(name)
+	}
+
+	if r.loggers == nil {
+		r.loggers = make(map[string]*zap.Logger)
+	}
+	r.loggers[name] = logger
+
+	return logger
+}
+
+// Validate all logger configs, errors are prefixed by logger name.
+func (r *Registry) Validate() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var errs []error
+	for _, name := range r.names() {
+		for _, err := range multierr.Errors(r.Loggers[name].Validate()) {
+			errs = append(errs, fmt.Errorf("loggers.%s.%v", name, err))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Sync all built loggers.
+func (r *Registry) Sync() error {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var errs []error
+	for _, logger := range r.loggers {
+		errs = append(errs, logger.Sync())
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Sync loggers and close writes of all logger configs, the shared writes are closed once.
+func (r *Registry) Close() error {
+	_ = r.Sync()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	closed := make(map[*syncer.Write]bool)
+
+	var errs []error
+	for _, name := range r.names() {
+		config := *r.Loggers[name]
+
+		// skip the shared writes already closed.
+		config.Writes = nil
+		for _, write := range r.Loggers[name].Writes {
+			if !closed[write] {
+				closed[write] = true
+				config.Writes = append(config.Writes, write)
+			}
+		}
+
+		if err := config.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("loggers.%s: %v", name, err))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement YAML Unmarshaler interface.
+func (r *Registry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	temp := struct {
+		Loggers map[string]interface{} `yaml:"loggers"`
+	}{}
+
+	if err := unmarshal(&temp); err != nil {
+		return err
+	}
+
+	return r.unmarshal(temp.Loggers, yaml.Marshal, yaml.Unmarshal)
+}
+
+// Implement JSON Unmarshaler interface.
+func (r *Registry) UnmarshalJSON(data []byte) error {
+	temp := struct {
+		Loggers map[string]interface{} `json:"loggers"`
+	}{}
+
+	if err := jsoniter.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+
+	return r.unmarshal(temp.Loggers, jsoniter.Marshal, jsoniter.Unmarshal)
+}
+
+// unmarshal logger entries, every entry is merged over the default entry before decode,
+// the entry without writes shares the default writes instead of decoding them again,
+// e.g. two lumberjack loggers should not rotate the same file.
+func (r *Registry) unmarshal(entries map[string]interface{}, marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) error {
+	loggers := make(map[string]*Config, len(entries))
+
+	base := entries[DefaultLoggerName]
+
+	decode := func(name string, entry interface{}) (*Config, error) {
+		data, err := marshal(entry)
+		if err != nil {
+			return nil, fmt.Errorf("loggers.%s: %v", name, err)
+		}
+
+		config := &Config{}
+		if err := unmarshal(data, config); err != nil {
+			return nil, fmt.Errorf("loggers.%s: %v", name, err)
+		}
+
+		// level default is info.
+		if config.Level == (zap.AtomicLevel{}) {
+			config.Level = zap.NewAtomicLevel()
+		}
+
+		return config, nil
+	}
+
+	// default entry is decoded first for the shared writes.
+	var defaultWrites []*syncer.Write
+	if _, ok := entries[DefaultLoggerName]; ok {
+		config, err := decode(DefaultLoggerName, base)
+		if err != nil {
+			return err
+		}
+		loggers[DefaultLoggerName] = config
+		defaultWrites = config.Writes
+	}
+
+	for name, entry := range entries {
+		if name == DefaultLoggerName {
+			continue
+		}
+
+		inherit := !hasKey(entry, "writes")
+		if inherit {
+			entry = mergeValue(deleteKey(base, "writes"), entry)
+		} else {
+			entry = mergeValue(base, entry)
+		}
+
+		config, err := decode(name, entry)
+		if err != nil {
+			return err
+		}
+		if inherit && defaultWrites != nil {
+			config.Writes = append([]*syncer.Write(nil), defaultWrites...)
+		}
+
+		loggers[name] = config
+	}
+
+	r.mutex.Lock()
+	r.Loggers = loggers
+	r.loggers = nil
+	r.mutex.Unlock()
+
+	return nil
+}
+
+// has key in the map value.
+func hasKey(value interface{}, key string) bool {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		_, ok := v[key]
+		return ok
+	case map[string]interface{}:
+		_, ok := v[key]
+		return ok
+	}
+	return false
+}
+
+// copy the map value without key, other values are returned as is.
+func deleteKey(value interface{}, key string) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[interface{}]interface{}, len(v))
+		for k, item := range v {
+			if k != key {
+				m[k] = item
+			}
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			if k != key {
+				m[k] = item
+			}
+		}
+		return m
+	}
+	return value
+}
+
+// merge src over dst into a new value, maps are merged deeply and other values are replaced.
+func mergeValue(dst, src interface{}) interface{} {
+	if src == nil {
+		return dst
+	}
+
+	switch s := src.(type) {
+	case map[interface{}]interface{}:
+		d, ok := dst.(map[interface{}]interface{})
+		if !ok {
+			return src
+		}
+		m := make(map[interface{}]interface{}, len(d)+len(s))
+		for k, v := range d {
+			m[k] = v
+		}
+		for k, v := range s {
+			m[k] = mergeValue(m[k], v)
+		}
+		return m
+	case map[string]interface{}:
+		d, ok := dst.(map[string]interface{})
+		if !ok {
+			return src
+		}
+		m := make(map[string]interface{}, len(d)+len(s))
+		for k, v := range d {
+			m[k] = v
+		}
+		for k, v := range s {
+			m[k] = mergeValue(m[k], v)
+		}
+		return m
+	}
+
+	return src
+}
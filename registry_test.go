@@ -0,0 +1,138 @@
+package zap
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestRegistry_UnmarshalYAML(t *testing.T) {
+	registry := NewRegistry()
+
+	err := yaml.Unmarshal([]byte(`
+loggers:
+  default:
+    level: info
+    console: true
+    fields:
+      app: test
+  http:
+    level: debug
+  audit:
+    console: false
+    fields:
+      audit: true
+    writes:
+      - name: lumberjack
+        config:
+          filename: audit.log
+`), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("registry", registry)
+
+	if registry.Config("http").Level.Level() != zap.DebugLevel || !registry.Config("http").Console {
+		t.Fatal("http logger should inherit default and override level")
+	}
+	audit := registry.Config("audit")
+	if audit.Level.Level() != zap.InfoLevel || audit.Console || len(audit.Writes) != 1 {
+		t.Fatalf("unexpected audit config: %s", audit)
+	}
+	if audit.Fields["app"] != "test" || audit.Fields["audit"] != true {
+		t.Fatalf("audit fields should be merged: %v", audit.Fields)
+	}
+	if registry.Config("default").Level == registry.Config("http").Level {
+		t.Fatal("loggers should have independent level")
+	}
+	if registry.Config("unknown") != registry.Config(DefaultLoggerName) {
+		t.Fatal("unknown logger should use default config")
+	}
+}
+
+func TestRegistry_GetNamed(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	db := GetDefaultConfig()
+	db.Console = false
+	db.AddSyncerWrite(&syncer.Write{Name: "buffer", Config: buf})
+
+	registry := NewRegistry().
+		Set(DefaultLoggerName, GetDefaultConfig()).
+		Set("db", db)
+
+	logger := registry.GetNamed("db")
+	if logger != registry.GetNamed("db") {
+		t.Fatal("named logger should be cached")
+	}
+
+	logger.Info("query")
+	if !strings.Contains(buf.String(), `"logger":"db"`) {
+		t.Fatalf("db logger should be named: %s", buf.String())
+	}
+
+	if registry.GetNamed("other") == nil {
+		t.Fatal("undeclared logger should fallback to default")
+	}
+}
+
+func TestRegistry_SharedWrites(t *testing.T) {
+	registry := NewRegistry()
+
+	err := yaml.Unmarshal([]byte(`
+loggers:
+  default:
+    level: info
+    writes:
+      - name: lumberjack
+        config:
+          filename: shared.log
+  http:
+    level: debug
+  audit:
+    writes:
+      - name: lumberjack
+        config:
+          filename: audit.log
+`), registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer registry.Close()
+
+	shared := registry.Config(DefaultLoggerName).Writes
+	if http := registry.Config("http").Writes; len(http) != 1 || http[0] != shared[0] {
+		t.Fatalf("http logger should share the default writes: %v", http)
+	}
+	if audit := registry.Config("audit").Writes; len(audit) != 1 || audit[0] == shared[0] || audit[0].GetWriter().(*lumberjack.Logger).Filename != "audit.log" {
+		t.Fatalf("audit logger should have own writes: %v", audit)
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	registry := NewRegistry()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			registry.Set(fmt.Sprintf("logger%d", i), GetDefaultConfig())
+			_ = registry.Names()
+		}(i)
+	}
+	wg.Wait()
+
+	if names := registry.Names(); len(names) != 10 {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
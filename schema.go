@@ -0,0 +1,258 @@
+package zap
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer"
+)
+
+// JSON Schema draft of the generated schema.
+const SchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Level names accepted by the level text unmarshaler.
+var levelNames = []string{
+	"debug", "info", "warn", "error", "dpanic", "panic", "fatal",
+	"DEBUG", "INFO", "WARN", "ERROR", "DPANIC", "PANIC", "FATAL",
+}
+
+// Types with their own schema.
+var (
+	levelType       = reflect.TypeOf(zapcore.Level(0))
+	atomicLevelType = reflect.TypeOf(zap.AtomicLevel{})
+	durationType    = reflect.TypeOf(time.Duration(0))
+	levelTreeType   = reflect.TypeOf(LevelTree{})
+	writeType       = reflect.TypeOf(syncer.Write{})
+	encoderType     = reflect.TypeOf(encoder.Config{})
+)
+
+// Generate JSON Schema of Config, writes are validated by one of the writers registered in syncer,
+// writer configs are derived from their mapstructure, yaml or json struct tags.
+func Schema() map[string]interface{} {
+	s := schemaGenerator{}.typeSchema(reflect.TypeOf(Config{}))
+	s["$schema"] = SchemaDraft
+	s["title"] = "zap logger config"
+
+	return s
+}
+
+// Marshal JSON Schema of Config as indented json.
+func MarshalSchema() ([]byte, error) {
+	return json.MarshalIndent(Schema(), "", "  ")
+}
+
+// JSON Schema generator.
+type schemaGenerator struct {
+	// allow ${VAR} environment variables for non string values, write values are expanded before decoding.
+	env bool
+}
+
+// wrap scalar schema which allows environment variables.
+func (g schemaGenerator) scalar(s map[string]interface{}) map[string]interface{} {
+	if !g.env {
+		return s
+	}
+
+	return map[string]interface{}{
+		"anyOf": []interface{}{s, map[string]interface{}{"type": "string", "pattern": `\$\{[^}]+\}`}},
+	}
+}
+
+// string schema, write values are weakly decoded, numbers and booleans are accepted as strings.
+func (g schemaGenerator) str() map[string]interface{} {
+	if !g.env {
+		return map[string]interface{}{"type": "string"}
+	}
+
+	return map[string]interface{}{"type": []string{"string", "number", "boolean"}}
+}
+
+// Patterns of the strings which are weakly decoded as booleans, integers and numbers.
+const (
+	boolPattern   = `^(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)$`
+	intPattern    = `^[+-]?[0-9]+$`
+	numberPattern = `^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`
+)
+
+// weakly typed scalar schema, write values are weakly decoded, the strings matching pattern are accepted.
+func (g schemaGenerator) weak(s map[string]interface{}, pattern string) map[string]interface{} {
+	if g.env {
+		s = map[string]interface{}{
+			"anyOf": []interface{}{s, map[string]interface{}{"type": "string", "pattern": pattern}},
+		}
+	}
+
+	return g.scalar(s)
+}
+
+// level schema.
+func (g schemaGenerator) level() map[string]interface{} {
+	return g.scalar(map[string]interface{}{"type": "string", "enum": levelNames})
+}
+
+// duration schema, as duration string or nanoseconds.
+func (g schemaGenerator) duration() map[string]interface{} {
+	return g.scalar(map[string]interface{}{
+		"type":    []string{"string", "integer"},
+		"pattern": `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`,
+	})
+}
+
+// type schema.
+func (g schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case levelType, atomicLevelType:
+		return g.level()
+	case durationType:
+		return g.duration()
+	case levelTreeType:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.level()}
+	case writeType:
+		return g.writeSchema()
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return g.weak(map[string]interface{}{"type": "boolean"}, boolPattern)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return g.weak(map[string]interface{}{"type": "integer"}, intPattern)
+	case reflect.Float32, reflect.Float64:
+		return g.weak(map[string]interface{}{"type": "number"}, numberPattern)
+	case reflect.String:
+		return g.str()
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.structSchema(t)
+	}
+
+	// interface and others are any.
+	return map[string]interface{}{}
+}
+
+// struct schema, unknown properties are not allowed.
+func (g schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	g.addProperties(t, properties)
+
+	// encoder names.
+	if t == encoderType {
+		for key, names := range encoder.Names() {
+			properties[key] = g.scalar(map[string]interface{}{"type": "string", "enum": names})
+		}
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+// add struct field properties, squashed or inlined structs are flattened.
+func (g schemaGenerator) addProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		name, flatten := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if flatten && ft.Kind() == reflect.Struct {
+			g.addProperties(ft, properties)
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+
+		properties[name] = g.typeSchema(field.Type)
+	}
+}
+
+// get field name by mapstructure, yaml or json tag in order, default is lowercase field name,
+// flatten is true when the field is squashed or inlined.
+func fieldName(field reflect.StructField) (name string, flatten bool) {
+	for _, key := range []string{"mapstructure", "yaml", "json"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		for _, option := range parts[1:] {
+			if option == "squash" || option == "inline" {
+				flatten = true
+			}
+		}
+		if parts[0] != "" || flatten {
+			return parts[0], flatten
+		}
+	}
+
+	return strings.ToLower(field.Name), false
+}
+
+// write schema, one of the registered writers by name.
+func (g schemaGenerator) writeSchema() map[string]interface{} {
+	g.env = true
+
+	names := syncer.Names()
+
+	writers := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		writer, err := syncer.NewWriter(name)
+		if err != nil {
+			continue
+		}
+
+		s := map[string]interface{}{
+			"title": name,
+			"properties": map[string]interface{}{
+				"name":   map[string]interface{}{"const": name},
+				"config": g.typeSchema(reflect.TypeOf(writer)),
+			},
+		}
+		if description, ok := syncer.Description(name); ok && description != "" {
+			s["description"] = description
+		}
+
+		writers = append(writers, s)
+	}
+
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]interface{}{
+			"name":      map[string]interface{}{"type": "string", "enum": names},
+			"level":     g.level(),
+			"max_level": g.level(),
+			"encoding":  g.scalar(map[string]interface{}{"type": "string", "enum": []string{encoder.JSON, encoder.Console}}),
+			"encoder":   g.typeSchema(encoderType),
+			"config":    map[string]interface{}{"type": "object"},
+		},
+		"additionalProperties": false,
+		"oneOf":                writers,
+	}
+}
@@ -0,0 +1,287 @@
+package zap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/json-iterator/go"
+	"github.com/santhosh-tekuri/jsonschema"
+	"gopkg.in/yaml.v2"
+	yaml3 "gopkg.in/yaml.v3"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+// get schema object by path of keys.
+func schemaPath(t *testing.T, s map[string]interface{}, keys ...string) map[string]interface{} {
+	t.Helper()
+
+	for _, key := range keys {
+		v, ok := s[key].(map[string]interface{})
+		if !ok {
+			t.Fatalf("schema %v not found", keys)
+		}
+		s = v
+	}
+
+	return s
+}
+
+// get writer schema of oneOf by name.
+func writerSchema(t *testing.T, write map[string]interface{}, name string) map[string]interface{} {
+	t.Helper()
+
+	for _, item := range write["oneOf"].([]interface{}) {
+		s := item.(map[string]interface{})
+		if s["title"] == name {
+			return s
+		}
+	}
+
+	t.Fatalf("writer %s not found in oneOf", name)
+	return nil
+}
+
+func TestSchema(t *testing.T) {
+	s := Schema()
+
+	if s["$schema"] != SchemaDraft {
+		t.Errorf("$schema: %v", s["$schema"])
+	}
+
+	// config.yaml keys are known properties.
+	data, err := ioutil.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		t.Fatal(err)
+	}
+	properties := schemaPath(t, s, "properties")
+	for key := range config {
+		if _, ok := properties[key]; !ok {
+			t.Errorf("config key %s not in schema", key)
+		}
+	}
+	if _, ok := properties["overrides"]; ok {
+		t.Error("unexported field should not be in schema")
+	}
+
+	// one of every registered writer.
+	write := schemaPath(t, s, "properties", "writes", "items")
+	if n := len(write["oneOf"].([]interface{})); n != len(syncer.Names()) {
+		t.Errorf("oneOf writers: %d, registered: %d", n, len(syncer.Names()))
+	}
+
+	tests := []struct {
+		writer string
+		keys   []string
+	}{
+		// squashed lumberjack config.
+		{"lumberjack", []string{"properties", "config", "properties", "filename"}},
+		{"lumberjack", []string{"properties", "config", "properties", "maxbackups"}},
+		{"websocket", []string{"properties", "config", "properties", "write_wait"}},
+	}
+	for _, test := range tests {
+		schemaPath(t, writerSchema(t, write, test.writer), test.keys...)
+	}
+
+	name := schemaPath(t, writerSchema(t, write, "lumberjack"), "properties", "name")
+	if name["const"] != "lumberjack" {
+		t.Errorf("lumberjack name: %v", name)
+	}
+
+	// encoder names.
+	timeEncoder := schemaPath(t, s, "properties", "encoder", "properties", "time_encoder")
+	if enum, ok := timeEncoder["enum"].([]string); !ok || len(enum) == 0 {
+		t.Errorf("time_encoder: %v", timeEncoder)
+	}
+
+	// write values allow environment variables.
+	maxBackups := schemaPath(t, writerSchema(t, write, "lumberjack"), "properties", "config", "properties", "maxbackups")
+	if _, ok := maxBackups["anyOf"]; !ok {
+		t.Errorf("maxbackups should allow environment variables: %v", maxBackups)
+	}
+}
+
+func TestMarshalSchema(t *testing.T) {
+	data, err := MarshalSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !jsoniter.Valid(data) {
+		t.Fatalf("invalid schema json: %s", data)
+	}
+}
+
+// compile the generated schema by a JSON Schema validator.
+func compileSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+
+	data, err := MarshalSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	if err := compiler.AddResource("zap.schema.json", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := compiler.Compile("zap.schema.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return schema
+}
+
+// decode yaml document as json value.
+func yamlDocument(t *testing.T, data []byte) interface{} {
+	t.Helper()
+
+	var v interface{}
+	if err := yaml3.Unmarshal(data, &v); err != nil {
+		t.Fatal(err)
+	}
+	b, err := jsoniter.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := jsonschema.DecodeJSON(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return doc
+}
+
+// get instance pointers and messages of the validation error and its causes.
+func validationDetail(err *jsonschema.ValidationError) string {
+	detail := err.InstancePtr + ": " + err.Message + "\n"
+	for _, cause := range err.Causes {
+		detail += validationDetail(cause)
+	}
+	return detail
+}
+
+// Config with every registered writer, values are weakly typed or environment variables as the decoder accepts.
+const schemaWritersConfig = `
+level: info
+fields:
+  app: test
+writes:
+  - name: lumberjack
+    max_level: warn
+    config:
+      filename: test.log
+      maxbackups: ${ZAP_TEST_MAX_BACKUPS:-10}
+  - name: websocket
+    config:
+      url: ws://localhost:8080/log
+      write_wait: 10s
+  - name: grpc
+    encoding: json
+    config:
+      address: localhost:9090
+      insecure: true
+      batch_size: "100"
+      flush_interval: 1000000000
+      fields:
+        env: dev
+  - name: syslog
+    level: error
+    encoding: console
+    config:
+      network: udp
+      address: localhost:514
+      format: 5424
+  - name: net
+    config:
+      network: tcp
+      address: localhost:5170
+      buffer_size: 1024
+  - name: fluent
+    config:
+      address: localhost:24224
+      tag: app.{logger}
+      second_precision: "true"
+  - name: http
+    encoder:
+      time_key: time
+    config:
+      mode: loki
+      url: http://localhost:3100/loki/api/v1/push
+      labels:
+        app: test
+      label_fields: [level]
+      max_retries: 3
+  - name: gelf
+    config:
+      address: localhost:12201
+      chunk_size: 8154
+      fields:
+        version: 2
+`
+
+func TestSchema_Validate(t *testing.T) {
+	schema := compileSchema(t)
+
+	data, err := ioutil.ReadFile("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"config.yaml", string(data)},
+		{"writers", schemaWritersConfig},
+	}
+	for _, test := range tests {
+		// accepted by the decoder.
+		config, err := ParseConfig(test.name+".yaml", []byte(test.data))
+		if err == nil {
+			err = config.Validate()
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		_ = config.Close()
+
+		// and by the schema.
+		if err := schema.ValidateInterface(yamlDocument(t, []byte(test.data))); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestSchema_ValidateInvalid(t *testing.T) {
+	schema := compileSchema(t)
+
+	tests := []struct {
+		data   string
+		expect string
+	}{
+		{"level: verbose\n", "/level"},
+		{"unknown: true\n", "unknown"},
+		{"writes:\n  - name: unknown\n", "/writes/0/name"},
+		{"writes:\n  - name: gelf\n    config:\n      chunk: 1\n", "chunk"},
+		{"writes:\n  - name: syslog\n    config:\n      timeout: soon\n", "/writes/0/config/timeout"},
+		{"writes:\n  - name: lumberjack\n    encoding: text\n", "/writes/0/encoding"},
+	}
+	for _, test := range tests {
+		err := schema.ValidateInterface(yamlDocument(t, []byte(test.data)))
+		if err == nil {
+			t.Errorf("%q should be invalid", test.data)
+			continue
+		}
+		if detail := validationDetail(err.(*jsonschema.ValidationError)); !strings.Contains(detail, test.expect) {
+			t.Errorf("%q expect error of %s, got %s", test.data, test.expect, detail)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package zap
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Rotator interface, reopen the log file, lumberjack writer implements it.
+type Rotator interface {
+	Rotate() error
+}
+
+// Handle signals with the logger of config, see HandleSignalsWith.
+func (c *Config) HandleSignals() (stop func()) {
+	return c.HandleSignalsWith(nil)
+}
+
+// Handle signals, SIGUSR1 raises verbosity, SIGUSR2 lowers verbosity and SIGHUP rotates
+// the writes implement Rotator, e.g. reopen lumberjack files after external logrotate.
+// Actions are logged by logger, nil logger reuses the core built by NewZapLogger and the actions
+// are not logged when it is not built, stop undoes the handling.
+// Signals are not supported on windows and stop does nothing.
+func (c *Config) HandleSignalsWith(logger *zap.Logger) (stop func()) {
+	if logger == nil {
+		logger = c.builtLogger()
+	}
+	return handleSignals(logger, func() *Config { return c }, c.Rotate)
+}
+
+// Handle signals of the live logger, see Config HandleSignalsWith, the actions are applied
+// to the current config which is resolved at each signal, so the reloaded writes are rotated.
+func (w *ConfigWatcher) HandleSignals() (stop func()) {
+	return handleSignals(w.logger, w.Config, w.Rotate)
+}
+
+// Rotate all writes implement Rotator in order.
+func (c *Config) Rotate() error {
+	var errs []error
+
+	for i, write := range c.Writes {
+		if write == nil {
+			continue
+		}
+
+		if rotator, ok := write.GetWriter().(Rotator); ok {
+			if err := rotator.Rotate(); err != nil {
+				errs = append(errs, fmt.Errorf("writes[%d]: rotate %s: %v", i, write.Name, err))
+			}
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Raise verbosity, decrease the level by one step until debug level.
+func (c *Config) RaiseVerbosity() zapcore.Level {
+	level := c.Level.Level()
+	if level > zapcore.DebugLevel {
+		level--
+		c.Level.SetLevel(level)
+	}
+	return level
+}
+
+// Lower verbosity, increase the level by one step until fatal level.
+func (c *Config) LowerVerbosity() zapcore.Level {
+	level := c.Level.Level()
+	if level < zapcore.FatalLevel {
+		level++
+		c.Level.SetLevel(level)
+	}
+	return level
+}
+
+// handle signal with action, writes are rotated by rotate, log what it did.
+func (c *Config) handleSignal(logger *zap.Logger, sig os.Signal, action string, rotate func() error) {
+	switch action {
+	case signalRaiseVerbosity, signalLowerVerbosity:
+		previous := c.Level.Level()
+
+		level := previous
+		if action == signalRaiseVerbosity {
+			level = c.RaiseVerbosity()
+		} else {
+			level = c.LowerVerbosity()
+		}
+
+		// log at the new level when it is higher than info, it is not logged above error.
+		lvl := zapcore.InfoLevel
+		if level > lvl {
+			lvl = level
+		}
+		if lvl > zapcore.ErrorLevel {
+			return
+		}
+		if ce := logger.Check(lvl, "logger level changed by signal"); ce != nil {
+			ce.Write(zap.Stringer("signal", sig), zap.Stringer("previous", previous), zap.Stringer("level", level))
+		}
+
+	case signalRotate:
+		if err := rotate(); err != nil {
+			logger.Error("logger files rotate by signal failed", zap.Stringer("signal", sig), zap.Error(err))
+			return
+		}
+		logger.Info("logger files rotated by signal", zap.Stringer("signal", sig))
+	}
+}
+
+// Signal actions.
+const (
+	signalRaiseVerbosity = "raise_verbosity"
+	signalLowerVerbosity = "lower_verbosity"
+	signalRotate         = "rotate"
+)
@@ -0,0 +1,54 @@
+//go:build !windows
+// +build !windows
+
+package zap
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
+// handle signals, the config is resolved at each signal.
+func handleSignals(logger *zap.Logger, config func() *Config, rotate func() error) (stop func()) {
+	logger = logger.Named("signal")
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2, syscall.SIGHUP)
+
+	exit := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			select {
+			case <-exit:
+				return
+			case sig := <-signals:
+				switch sig {
+				case syscall.SIGUSR1:
+					config().handleSignal(logger, sig, signalRaiseVerbosity, rotate)
+				case syscall.SIGUSR2:
+					config().handleSignal(logger, sig, signalLowerVerbosity, rotate)
+				case syscall.SIGHUP:
+					config().handleSignal(logger, sig, signalRotate, rotate)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(exit)
+			<-done
+		})
+	}
+}
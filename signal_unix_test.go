@@ -0,0 +1,195 @@
+//go:build !windows
+// +build !windows
+
+package zap
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/lumberjack"
+)
+
+func TestConfig_HandleSignals(t *testing.T) {
+	dir := t.TempDir()
+
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: lumberjack.Name, Config: lumberjack.New(filepath.Join(dir, "app.log"))})
+	config.NewZapLogger().Info("before rotate")
+
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	stop := config.HandleSignalsWith(zap.New(core))
+	defer stop()
+
+	// send signal and waiting the log.
+	signal := func(sig syscall.Signal) {
+		n := logs.Len()
+		if err := syscall.Kill(syscall.Getpid(), sig); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; logs.Len() == n; i++ {
+			if i > 100 {
+				t.Fatalf("signal %s is not handled", sig)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	tests := []struct {
+		sig   syscall.Signal
+		level zapcore.Level
+	}{
+		{syscall.SIGUSR1, zap.DebugLevel},
+		{syscall.SIGUSR1, zap.DebugLevel},
+		{syscall.SIGUSR2, zap.InfoLevel},
+		{syscall.SIGUSR2, zap.WarnLevel},
+	}
+
+	for _, test := range tests {
+		signal(test.sig)
+		if level := config.Level.Level(); level != test.level {
+			t.Errorf("signal %s expect level %s, got %s", test.sig, test.level, level)
+		}
+	}
+
+	signal(syscall.SIGHUP)
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 {
+		t.Errorf("expect rotated backup file, got %d files", len(files))
+	}
+
+	if last := logs.AllUntimed()[logs.Len()-1]; last.Message != "logger files rotated by signal" {
+		t.Errorf("unexpected log %s", last.Message)
+	}
+
+	stop()
+	stop()
+}
+
+func TestConfigWatcher_HandleSignals(t *testing.T) {
+	dir := t.TempDir()
+
+	filename := filepath.Join(dir, "config.yaml")
+	a := filepath.Join(dir, "a.log")
+	b := filepath.Join(dir, "b.log")
+
+	writeFile(t, filename, `
+level: info
+writes:
+  - name: lumberjack
+    config:
+      filename: `+a+`
+      compress: false`)
+
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	stop := w.HandleSignals()
+	defer stop()
+
+	w.Logger().Info("before reload")
+
+	writeFile(t, filename, `
+level: info
+writes:
+  - name: lumberjack
+    config:
+      filename: `+b+`
+      compress: false`)
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the reloaded write is rotated, the closed write is not reopened.
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; !strings.Contains(readFile(t, b), "logger files rotated by signal"); i++ {
+		if i > 100 {
+			t.Fatalf("signal is not handled: %s", readFile(t, b))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	backups, err := filepath.Glob(filepath.Join(dir, "b-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 || len(backups) != 1 {
+		t.Errorf("expect a.log, b.log and b backup, got %v", files)
+	}
+}
+
+// Writer counts the starts.
+type startCountWriter struct {
+	mutex  sync.Mutex
+	buf    bytes.Buffer
+	starts int32
+}
+
+func (w *startCountWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *startCountWriter) Start() error {
+	atomic.AddInt32(&w.starts, 1)
+	return nil
+}
+
+func (w *startCountWriter) String() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	return w.buf.String()
+}
+
+func TestConfig_HandleSignalsBuiltCore(t *testing.T) {
+	writer := &startCountWriter{}
+
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: "count", Config: writer})
+	config.NewZapLogger()
+
+	stop := config.HandleSignals()
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR2); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; !strings.Contains(writer.String(), "logger level changed by signal"); i++ {
+		if i > 100 {
+			t.Fatalf("signal action should be logged by the built core: %s", writer.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the writer is not started again.
+	if n := atomic.LoadInt32(&writer.starts); n != 1 {
+		t.Errorf("writer should be started once, got %d", n)
+	}
+}
@@ -0,0 +1,13 @@
+//go:build windows
+// +build windows
+
+package zap
+
+import (
+	"go.uber.org/zap"
+)
+
+// handle signals, it is not supported on windows and stop does nothing.
+func handleSignals(logger *zap.Logger, config func() *Config, rotate func() error) (stop func()) {
+	return func() {}
+}
@@ -0,0 +1,176 @@
+package zap
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Swap core state, generation increases when the core is swapped.
+type swapState struct {
+	gen  uint64
+	core zapcore.Core
+	// in-flight writes admitted by Check and not written yet.
+	refs int64
+}
+
+// release an in-flight write.
+func (s *swapState) release() {
+	atomic.AddInt64(&s.refs, -1)
+}
+
+// Wait the in-flight writes of the swapped out state with timeout, return false when timeout.
+func (s *swapState) wait(timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+	for atomic.LoadInt64(&s.refs) > 0 {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+// Release core is added to the checked entry after the underlying cores,
+// the in-flight write is released when the entry is written.
+type releaseCore struct {
+	state *swapState
+}
+
+// Implement zapcore LevelEnabler interface.
+func (r releaseCore) Enabled(zapcore.Level) bool {
+	return true
+}
+
+// Implement zapcore Core interface.
+func (r releaseCore) With([]zapcore.Field) zapcore.Core {
+	return r
+}
+
+// Implement zapcore Core interface.
+func (r releaseCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+// Implement zapcore Core interface.
+func (r releaseCore) Write(zapcore.Entry, []zapcore.Field) error {
+	r.state.release()
+	return nil
+}
+
+// Implement zapcore Core interface.
+func (r releaseCore) Sync() error {
+	return nil
+}
+
+// Swappable zap core, the underlying core can be replaced on a live logger,
+// child cores created by With follow the replacement.
+type swapCore struct {
+	// shared state.
+	state *atomic.Value
+	// fields added by With.
+	fields []zapcore.Field
+	// cached core with fields.
+	cache *atomic.Value
+}
+
+// New swappable zap core.
+func newSwapCore(core zapcore.Core) *swapCore {
+	s := &swapCore{
+		state: &atomic.Value{},
+		cache: &atomic.Value{},
+	}
+	s.state.Store(&swapState{core: core})
+
+	return s
+}
+
+// Swap the underlying core, return the old state,
+// wait the in-flight writes of the old state before closing its writes.
+func (s *swapCore) swap(core zapcore.Core) *swapState {
+	old := s.state.Load().(*swapState)
+	s.state.Store(&swapState{gen: old.gen + 1, core: core})
+
+	return old
+}
+
+// acquire current state for an in-flight write, the state is not swapped out before released.
+func (s *swapCore) acquire() *swapState {
+	for {
+		state := s.state.Load().(*swapState)
+		atomic.AddInt64(&state.refs, 1)
+
+		// the state may be swapped out before acquired.
+		if s.state.Load().(*swapState) == state {
+			return state
+		}
+		state.release()
+	}
+}
+
+// get core of state with fields.
+func (s *swapCore) core(state *swapState) zapcore.Core {
+	if len(s.fields) == 0 {
+		return state.core
+	}
+
+	// cache hit.
+	if cache, ok := s.cache.Load().(*swapState); ok && cache.gen == state.gen {
+		return cache.core
+	}
+
+	cache := &swapState{gen: state.gen, core: state.core.With(s.fields)}
+	s.cache.Store(cache)
+
+	return cache.core
+}
+
+// get current core with fields.
+func (s *swapCore) current() zapcore.Core {
+	return s.core(s.state.Load().(*swapState))
+}
+
+// Implement zapcore LevelEnabler interface.
+func (s *swapCore) Enabled(level zapcore.Level) bool {
+	return s.current().Enabled(level)
+}
+
+// Implement zapcore Core interface.
+func (s *swapCore) With(fields []zapcore.Field) zapcore.Core {
+	fs := make([]zapcore.Field, 0, len(s.fields)+len(fields))
+	fs = append(fs, s.fields...)
+	fs = append(fs, fields...)
+
+	return &swapCore{
+		state:  s.state,
+		fields: fs,
+		cache:  &atomic.Value{},
+	}
+}
+
+// Implement zapcore Core interface, the state is held until the checked entry is written.
+func (s *swapCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	state := s.acquire()
+
+	ce = s.core(state).Check(entry, ce)
+	if ce == nil {
+		state.release()
+		return nil
+	}
+
+	return ce.AddCore(entry, releaseCore{state: state})
+}
+
+// Implement zapcore Core interface.
+func (s *swapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	state := s.acquire()
+	defer state.release()
+
+	return s.core(state).Write(entry, fields)
+}
+
+// Implement zapcore Core interface.
+func (s *swapCore) Sync() error {
+	return s.current().Sync()
+}
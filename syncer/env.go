@@ -0,0 +1,142 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/go-framework/zap/encoder"
+)
+
+// Expand ${VAR}, ${VAR:-default} and ${VAR-default} in s with environment variables,
+// ${VAR:-default} use default when VAR is unset or empty, ${VAR-default} use default only when VAR is unset,
+// $$ is escaped as $.
+func ExpandEnv(s string) string {
+	if !strings.Contains(s, "$") {
+		return s
+	}
+
+	buf := make([]byte, 0, len(s))
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			buf = append(buf, s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			buf = append(buf, '$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				buf = append(buf, s[i:]...)
+				return string(buf)
+			}
+			buf = append(buf, expandVar(s[i+2:i+2+end])...)
+			i += 2 + end
+		default:
+			buf = append(buf, s[i])
+		}
+	}
+
+	return string(buf)
+}
+
+// expand variable expression.
+func expandVar(expr string) string {
+	if n := strings.Index(expr, ":-"); n >= 0 {
+		if value := os.Getenv(expr[:n]); value != "" {
+			return value
+		}
+		return expr[n+2:]
+	}
+
+	if n := strings.IndexByte(expr, '-'); n >= 0 {
+		if value, ok := os.LookupEnv(expr[:n]); ok {
+			return value
+		}
+		return expr[n+1:]
+	}
+
+	return os.Getenv(expr)
+}
+
+// Expand environment variables in all strings of the unmarshal value.
+func expandValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return ExpandEnv(v)
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = expandValue(item)
+		}
+	case map[interface{}]interface{}:
+		for key, item := range v {
+			v[key] = expandValue(item)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = expandValue(item)
+		}
+	}
+
+	return value
+}
+
+// decode input into result, strings are weakly converted as expanded values are strings.
+func decode(input interface{}, result interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           result,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(input)
+}
+
+// Override write with key and value, key is level, max_level, encoding
+// or the config field name with config_ prefix.
+func (this *Write) Override(key, value string) error {
+	key = strings.ToLower(key)
+
+	switch key {
+	case "level":
+		level, err := parseLevel(value)
+		if err != nil {
+			return err
+		}
+		this.Level = level
+	case "max_level":
+		level, err := parseLevel(value)
+		if err != nil {
+			return err
+		}
+		this.MaxLevel = level
+	case "encoding":
+		if err := encoder.CheckEncoding(value); err != nil {
+			return err
+		}
+		this.Encoding = value
+	default:
+		if !strings.HasPrefix(key, "config_") {
+			return errors.New("not support write override: " + key)
+		}
+		if this.Config == nil {
+			return fmt.Errorf("write %s has no config", this.Name)
+		}
+
+		return decode(map[string]interface{}{
+			strings.TrimPrefix(key, "config_"): value,
+		}, this.Config)
+	}
+
+	return nil
+}
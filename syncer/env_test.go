@@ -0,0 +1,47 @@
+package syncer
+
+import (
+	"os"
+	"testing"
+)
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("ZAP_TEST_HOST", "127.0.0.1")
+	os.Setenv("ZAP_TEST_EMPTY", "")
+	os.Unsetenv("ZAP_TEST_UNSET")
+
+	tests := []struct {
+		s      string
+		expect string
+	}{
+		{"ws://${ZAP_TEST_HOST}:9008", "ws://127.0.0.1:9008"},
+		{"${ZAP_TEST_UNSET:-/var/log}/app.log", "/var/log/app.log"},
+		{"${ZAP_TEST_EMPTY:-default}", "default"},
+		{"${ZAP_TEST_EMPTY-default}", ""},
+		{"${ZAP_TEST_UNSET-default}", "default"},
+		{"$${ZAP_TEST_HOST}", "${ZAP_TEST_HOST}"},
+		{"$ZAP_TEST_HOST", "$ZAP_TEST_HOST"},
+		{"${ZAP_TEST_HOST", "${ZAP_TEST_HOST"},
+	}
+
+	for _, test := range tests {
+		if s := ExpandEnv(test.s); s != test.expect {
+			t.Errorf("expand %q expect %q, got %q", test.s, test.expect, s)
+		}
+	}
+}
+
+func TestWrite_Override(t *testing.T) {
+	write := &Write{Name: "test", Encoding: "console"}
+
+	if err := write.Override("ENCODING", "jsno"); err == nil {
+		t.Fatal("unsupported encoding should be rejected")
+	}
+	if write.Encoding != "console" {
+		t.Errorf("encoding should not be changed: %s", write.Encoding)
+	}
+
+	if err := write.Override("ENCODING", "json"); err != nil || write.Encoding != "json" {
+		t.Errorf("encoding should be overridden: %s, %v", write.Encoding, err)
+	}
+}
@@ -0,0 +1,477 @@
+package fluent
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/batch"
+	"github.com/go-framework/zap/syncer/internal/entry"
+	"github.com/go-framework/zap/syncer/internal/msgpack"
+)
+
+const (
+	// Name.
+	Name = "fluent"
+
+	// Default network.
+	Network = "tcp"
+	// Default address.
+	Address = "localhost:24224"
+	// Default tag template.
+	Tag = "zap.{logger}"
+	// Max entries in one message.
+	BatchSize = 100
+	// Send interval of the pending entries.
+	FlushInterval = time.Second
+	// The max amount of buffered entries, entries are dropped when the buffer is full.
+	BufferSize = 10000
+	// Time allowed to dial, write a message and wait for the ack, sync and close.
+	Timeout = 10 * time.Second
+)
+
+// Forward protocol modes.
+const (
+	// Forward mode, entries are sent as an array.
+	Forward = "forward"
+	// PackedForward mode, entries are sent as a binary of concatenated entries.
+	PackedForward = "packed_forward"
+)
+
+// Fluent logger sends the zap json entries to fluentd or fluent bit by the forward protocol in batches,
+// the record is the entry without time, the entry time is the event time.
+// Entries of a batch are grouped by tag, a message is retried with backoff until it is sent,
+// or acknowledged when ack response is required.
+type Logger struct {
+	// Network: tcp or unix.
+	Network string `json:"network" yaml:"network" mapstructure:"network"`
+	// Address as host:port, or socket path for unix network.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Tag template, {logger} and {level} are replaced by the entry logger name and level,
+	// empty tag parts are removed, e.g. app.{logger}.
+	Tag string `json:"tag" yaml:"tag" mapstructure:"tag"`
+	// Mode: forward or packed_forward.
+	Mode string `json:"mode" yaml:"mode" mapstructure:"mode"`
+	// Send time as integer seconds instead of event time with nanoseconds, for fluentd before v0.14.
+	SecondPrecision bool `json:"second_precision" yaml:"second_precision" mapstructure:"second_precision"`
+	// Wait for the ack response of every message.
+	RequireAckResponse bool `json:"require_ack_response" yaml:"require_ack_response" mapstructure:"require_ack_response"`
+	// Max entries in one message.
+	BatchSize int `json:"batch_size" yaml:"batch_size" mapstructure:"batch_size"`
+	// Send interval of the pending entries.
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval" mapstructure:"flush_interval"`
+	// The max amount of buffered entries.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size" mapstructure:"buffer_size"`
+	// Time allowed to dial, write a message and wait for the ack, sync and close.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Minimum retry backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum retry backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+
+	// entry parser by the write encoder config.
+	parser entry.Parser
+
+	once  sync.Once
+	loop  batch.Loop
+	mutex sync.RWMutex
+	conn  net.Conn
+}
+
+// Tagged msgpack encoded [time, record] entry.
+type record struct {
+	tag  string
+	data []byte
+}
+
+// New logger with address.
+func New(address string) *Logger {
+	l := GetDefault()
+	l.Address = address
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		Network:       Network,
+		Address:       Address,
+		Tag:           Tag,
+		Mode:          Forward,
+		BatchSize:     BatchSize,
+		FlushInterval: FlushInterval,
+		BufferSize:    BufferSize,
+		Timeout:       Timeout,
+		MinBackoff:    backoff.DefaultMin,
+		MaxBackoff:    backoff.DefaultMax,
+	}
+}
+
+// Implement Cloner interface, only the config is copied, the clone sends by its own connection.
+func (l *Logger) Clone() io.Writer {
+	return &Logger{
+		Network:            l.Network,
+		Address:            l.Address,
+		Tag:                l.Tag,
+		Mode:               l.Mode,
+		SecondPrecision:    l.SecondPrecision,
+		RequireAckResponse: l.RequireAckResponse,
+		BatchSize:          l.BatchSize,
+		FlushInterval:      l.FlushInterval,
+		BufferSize:         l.BufferSize,
+		Timeout:            l.Timeout,
+		MinBackoff:         l.MinBackoff,
+		MaxBackoff:         l.MaxBackoff,
+	}
+}
+
+// Implement syncer EntryParser interface, entries are parsed as json.
+func (l *Logger) Encoding() string {
+	return encoder.JSON
+}
+
+// Implement syncer EntryParser interface.
+func (l *Logger) SetEncoderConfig(config zapcore.EncoderConfig) {
+	l.parser.SetEncoderConfig(config)
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	switch l.Network {
+	case "tcp", "tcp4", "tcp6", "unix":
+	default:
+		errs = append(errs, fmt.Errorf("network: not support %q", l.Network))
+	}
+	if l.Address == "" {
+		errs = append(errs, errors.New("address: must not be empty"))
+	}
+	if l.Tag == "" {
+		errs = append(errs, errors.New("tag: must not be empty"))
+	}
+	if l.Mode != Forward && l.Mode != PackedForward {
+		errs = append(errs, fmt.Errorf("mode: should be %s or %s", Forward, PackedForward))
+	}
+	if l.BatchSize <= 0 {
+		errs = append(errs, errors.New("batch_size: must be positive"))
+	}
+	if l.FlushInterval <= 0 {
+		errs = append(errs, errors.New("flush_interval: must be positive"))
+	}
+	if l.BufferSize <= 0 {
+		errs = append(errs, errors.New("buffer_size: must be positive"))
+	}
+	if l.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout: must be positive"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	return l.loop.Health(Name)
+}
+
+// Get dropped entries count.
+func (l *Logger) Dropped() uint64 {
+	return l.loop.Dropped()
+}
+
+// Implement Writer interface, p should be a zap json entry.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	keys := l.parser.Keys()
+	e := entry.Parse(p, keys)
+
+	data := make(map[string]interface{}, len(e.Fields)+5)
+	for k, v := range e.Fields {
+		data[k] = v
+	}
+	data[keys.Level] = e.Level.String()
+	data[keys.Message] = e.Message
+	if e.Logger != "" {
+		data[keys.Name] = e.Logger
+	}
+	if e.Caller != "" {
+		data[keys.Caller] = e.Caller
+	}
+	if e.Stack != "" {
+		data[keys.Stacktrace] = e.Stack
+	}
+
+	tag := strings.NewReplacer("{logger}", e.Logger, "{level}", e.Level.String()).Replace(l.Tag)
+
+	if err := l.Post(tag, e.Time, data); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Post structured record with tag and time, empty tag parts are removed.
+func (l *Logger) Post(tag string, t time.Time, data map[string]interface{}) error {
+	l.once.Do(l.start)
+
+	r := &record{tag: cleanTag(tag)}
+	r.data = msgpack.AppendArrayHeader(r.data, 2)
+	if l.SecondPrecision {
+		r.data = msgpack.AppendInt(r.data, t.Unix())
+	} else {
+		r.data = msgpack.AppendEventTime(r.data, t)
+	}
+	r.data = msgpack.Append(r.data, data)
+
+	return l.loop.Add(r)
+}
+
+// clean tag, empty parts are removed, default is zap.
+func cleanTag(tag string) string {
+	parts := strings.Split(tag, ".")
+	n := 0
+	for _, part := range parts {
+		if part != "" {
+			parts[n] = part
+			n++
+		}
+	}
+	if n == 0 {
+		return "zap"
+	}
+	return strings.Join(parts[:n], ".")
+}
+
+// Implement WriteSyncer interface, send the buffered entries.
+func (l *Logger) Sync() error {
+	return l.loop.Sync()
+}
+
+// Close, send the buffered entries and close the connection.
+func (l *Logger) Close() error {
+	l.once.Do(func() {})
+
+	err := l.loop.Close(Name)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn != nil {
+		err = multierr.Append(err, l.conn.Close())
+		l.conn = nil
+	}
+
+	return err
+}
+
+// get timeout.
+func (l *Logger) timeout() time.Duration {
+	if l.Timeout > 0 {
+		return l.Timeout
+	}
+	return Timeout
+}
+
+// start send loop.
+func (l *Logger) start() {
+	size, batchSize := l.BufferSize, l.BatchSize
+	if size <= 0 {
+		size = BufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+	interval := l.FlushInterval
+	if interval <= 0 {
+		interval = FlushInterval
+	}
+
+	l.loop.Start(batch.Config{
+		Name:          Name,
+		BufferSize:    size,
+		BatchSize:     batchSize,
+		FlushInterval: interval,
+		Timeout:       l.timeout(),
+		MinBackoff:    l.MinBackoff,
+		MaxBackoff:    l.MaxBackoff,
+		Push: func(records []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+			return l.push(records, batchSize, b, exit)
+		},
+	})
+}
+
+// push batch in messages by tag of batch size, retry with backoff until sent or exit closed,
+// return the entries which are not sent. exit nil pushes once without retry.
+func (l *Logger) push(records []interface{}, batchSize int, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+	for len(records) > 0 {
+		// entries of the first tag.
+		tag := records[0].(*record).tag
+		message := make([]*record, 0, batchSize)
+		var rest []interface{}
+		for _, e := range records {
+			if r := e.(*record); r.tag == tag && len(message) < batchSize {
+				message = append(message, r)
+			} else {
+				rest = append(rest, r)
+			}
+		}
+
+		err := l.send(tag, message)
+		l.loop.SetError(err)
+
+		if err == nil {
+			b.Reset()
+			records = rest
+			continue
+		}
+		if exit == nil || !b.Sleep(exit) {
+			break
+		}
+	}
+
+	// reuse the batch buffer.
+	if len(records) == 0 {
+		return make([]interface{}, 0, batchSize)
+	}
+
+	return records
+}
+
+// encode forward or packed forward message of entries with tag.
+func (l *Logger) encode(tag string, records []*record, chunk string) []byte {
+	var b []byte
+	b = msgpack.AppendArrayHeader(b, 3)
+	b = msgpack.AppendString(b, tag)
+
+	if l.Mode == PackedForward {
+		var packed []byte
+		for _, r := range records {
+			packed = append(packed, r.data...)
+		}
+		b = msgpack.AppendBytes(b, packed)
+	} else {
+		b = msgpack.AppendArrayHeader(b, len(records))
+		for _, r := range records {
+			b = append(b, r.data...)
+		}
+	}
+
+	option := map[string]interface{}{"size": len(records)}
+	if chunk != "" {
+		option["chunk"] = chunk
+	}
+
+	return msgpack.Append(b, option)
+}
+
+// send message of entries with tag, wait for the ack response when required.
+func (l *Logger) send(tag string, records []*record) error {
+	conn, err := l.dial()
+	if err != nil {
+		return err
+	}
+
+	var chunk string
+	if l.RequireAckResponse {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		chunk = base64.StdEncoding.EncodeToString(id)
+	}
+
+	// the watch reads without deadline when ack response is not required.
+	deadline := time.Now().Add(l.timeout())
+	if chunk == "" {
+		err = conn.SetWriteDeadline(deadline)
+	} else {
+		err = conn.SetDeadline(deadline)
+	}
+	if err != nil {
+		l.closeConn(conn)
+		return err
+	}
+
+	if _, err := conn.Write(l.encode(tag, records, chunk)); err != nil {
+		l.closeConn(conn)
+		return err
+	}
+
+	if chunk == "" {
+		return nil
+	}
+
+	resp, err := msgpack.Decode(conn)
+	if err != nil {
+		l.closeConn(conn)
+		return fmt.Errorf("read ack: %v", err)
+	}
+	if ack, ok := resp.(map[string]interface{}); !ok || ack["ack"] != chunk {
+		l.closeConn(conn)
+		return fmt.Errorf("unexpected ack response: %v", resp)
+	}
+
+	return nil
+}
+
+// dial fluent server, the connection is redialed after it is closed.
+func (l *Logger) dial() (net.Conn, error) {
+	l.mutex.RLock()
+	conn := l.conn
+	l.mutex.RUnlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := net.DialTimeout(l.Network, l.Address, l.timeout())
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	l.conn = conn
+	l.mutex.Unlock()
+
+	// the server sends nothing without ack response, watch the connection closed by the server.
+	if !l.RequireAckResponse {
+		go l.watch(conn)
+	}
+
+	return conn, nil
+}
+
+// close the connection, it is redialed by the next send.
+func (l *Logger) closeConn(conn net.Conn) {
+	conn.Close()
+
+	l.mutex.Lock()
+	if l.conn == conn {
+		l.conn = nil
+	}
+	l.mutex.Unlock()
+}
+
+// watch connection until it is closed.
+func (l *Logger) watch(conn net.Conn) {
+	buf := make([]byte, 512)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			break
+		}
+	}
+
+	l.closeConn(conn)
+}
@@ -0,0 +1,233 @@
+package fluent
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-framework/zap/syncer/internal/msgpack"
+)
+
+// Received forward message.
+type message struct {
+	tag     string
+	entries []interface{}
+	option  map[string]interface{}
+}
+
+// Fake forward server.
+type server struct {
+	listener net.Listener
+	messages chan *message
+	// messages are not acknowledged and connections are closed before.
+	reject int32
+}
+
+// new fake forward server.
+func newServer(t *testing.T) *server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &server{listener: listener, messages: make(chan *message, 100)}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// serve connection.
+func (s *server) serve(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		v, err := msgpack.Decode(conn)
+		if err != nil {
+			return
+		}
+
+		if atomic.AddInt32(&s.reject, -1) >= 0 {
+			return
+		}
+
+		array := v.([]interface{})
+		m := &message{tag: array[0].(string), option: array[2].(map[string]interface{})}
+
+		switch entries := array[1].(type) {
+		case []interface{}:
+			m.entries = entries
+		case []byte:
+			r := bytes.NewReader(entries)
+			for {
+				e, err := msgpack.Decode(r)
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return
+				}
+				m.entries = append(m.entries, e)
+			}
+		}
+
+		if chunk, ok := m.option["chunk"]; ok {
+			conn.Write(msgpack.Append(nil, map[string]interface{}{"ack": chunk}))
+		}
+
+		s.messages <- m
+	}
+}
+
+// receive message or fail after timeout.
+func (s *server) receive(t *testing.T) *message {
+	t.Helper()
+
+	select {
+	case m := <-s.messages:
+		return m
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message")
+	}
+	return nil
+}
+
+func TestLogger_Forward(t *testing.T) {
+	for _, mode := range []string{Forward, PackedForward} {
+		t.Run(mode, func(t *testing.T) {
+			s := newServer(t)
+			defer s.listener.Close()
+
+			l := New(s.listener.Addr().String())
+			l.Mode = mode
+			l.Tag = "app.{logger}"
+			defer l.Close()
+
+			lines := []string{
+				`{"level":"info","ts":1600000000.5,"logger":"db","msg":"first","count":1}`,
+				`{"level":"warn","ts":1600000001,"msg":"second"}`,
+				`{"level":"error","ts":1600000002,"logger":"db","msg":"third","caller":"db/query.go:10"}`,
+			}
+			for _, line := range lines {
+				if _, err := l.Write([]byte(line + "\n")); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := l.Sync(); err != nil {
+				t.Fatal(err)
+			}
+
+			// grouped by tag.
+			m := s.receive(t)
+			if m.tag != "app.db" || len(m.entries) != 2 || m.option["size"] != int64(2) {
+				t.Fatalf("message: %+v", m)
+			}
+
+			e := m.entries[0].([]interface{})
+			if !bytes.Equal(e[0].([]byte), msgpack.AppendEventTime(nil, time.Unix(1600000000, 5e8))[2:]) {
+				t.Errorf("event time: % x", e[0])
+			}
+			record := e[1].(map[string]interface{})
+			if record["msg"] != "first" || record["level"] != "info" || record["logger"] != "db" || record["count"] != int64(1) {
+				t.Errorf("record: %v", record)
+			}
+			if _, ok := record["ts"]; ok {
+				t.Error("time should not be in record")
+			}
+
+			m = s.receive(t)
+			if m.tag != "app" || len(m.entries) != 1 {
+				t.Errorf("message: %+v", m)
+			}
+		})
+	}
+}
+
+func TestLogger_RequireAckResponse(t *testing.T) {
+	s := newServer(t)
+	defer s.listener.Close()
+	s.reject = 1
+
+	l := New(s.listener.Addr().String())
+	l.RequireAckResponse = true
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = 10 * time.Millisecond
+	defer l.Close()
+
+	if err := l.Post("audit", time.Unix(1600000000, 0), map[string]interface{}{"user": "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	m := s.receive(t)
+	if m.tag != "audit" || len(m.entries) != 1 {
+		t.Fatalf("message: %+v", m)
+	}
+	if chunk, _ := m.option["chunk"].(string); chunk == "" {
+		t.Errorf("chunk should be set: %v", m.option)
+	}
+	if err := l.Health(); err != nil {
+		t.Errorf("health: %v", err)
+	}
+}
+
+func TestLogger_SecondPrecision(t *testing.T) {
+	s := newServer(t)
+	defer s.listener.Close()
+
+	l := New(s.listener.Addr().String())
+	l.SecondPrecision = true
+	defer l.Close()
+
+	l.Post("app", time.Unix(1600000000, 5e8), map[string]interface{}{})
+	l.Sync()
+
+	m := s.receive(t)
+	if e := m.entries[0].([]interface{}); e[0] != int64(1600000000) && e[0] != uint64(1600000000) {
+		t.Errorf("time: %#v", e[0])
+	}
+}
+
+func TestCleanTag(t *testing.T) {
+	tests := map[string]string{
+		"app.db":     "app.db",
+		"app.":       "app",
+		"app..http.": "app.http",
+		".":          "zap",
+	}
+	for tag, expect := range tests {
+		if s := cleanTag(tag); s != expect {
+			t.Errorf("tag %q: %q, expect: %q", tag, s, expect)
+		}
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	if err := GetDefault().Validate(); err != nil {
+		t.Errorf("default should be valid: %v", err)
+	}
+
+	l := &Logger{Network: "udp", MinBackoff: time.Second}
+	err := l.Validate()
+	if err == nil {
+		t.Fatal("logger should be invalid")
+	}
+	for _, expect := range []string{"network", "address", "tag", "mode", "batch_size", "flush_interval", "buffer_size", "timeout", "max_backoff"} {
+		if !strings.Contains(err.Error(), expect+":") {
+			t.Errorf("error should contain %s: %v", expect, err)
+		}
+	}
+}
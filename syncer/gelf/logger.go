@@ -0,0 +1,451 @@
+package gelf
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+
+	zapEncoder "github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/entry"
+	"github.com/go-framework/zap/syncer/syslog"
+)
+
+const (
+	// Name.
+	Name = "gelf"
+
+	// Default network.
+	Network = "udp"
+	// Default address.
+	Address = "localhost:12201"
+	// Max udp chunk size of WAN, 8154 is suggested in LAN.
+	ChunkSize = 1420
+	// Time allowed to dial and write a message.
+	Timeout = 5 * time.Second
+)
+
+// Udp compressions.
+const (
+	// Gzip compression.
+	Gzip = "gzip"
+	// Zlib compression.
+	Zlib = "zlib"
+	// No compression.
+	None = "none"
+)
+
+// Max chunks of one udp message.
+const maxChunks = 128
+
+// Chunk header: magic bytes, message id, sequence number and sequence count.
+const chunkHeaderSize = 12
+
+// Local hostname.
+var hostname, _ = os.Hostname()
+
+// Json encoder with sorted keys.
+var encoder = jsoniter.ConfigCompatibleWithStandardLibrary
+
+// Gelf logger sends every zap entry as a GELF 1.1 message to graylog, by chunked udp with compression,
+// or by null terminated tcp. The connection is redialed on failure, writes fail fast until the next
+// retry after backoff.
+type Logger struct {
+	// Network: udp or tcp.
+	Network string `json:"network" yaml:"network" mapstructure:"network"`
+	// Graylog input address as host:port.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Host, default is the local hostname.
+	Host string `json:"host" yaml:"host" mapstructure:"host"`
+	// Udp compression: gzip, zlib or none.
+	Compression string `json:"compression" yaml:"compression" mapstructure:"compression"`
+	// Max udp chunk size including the chunk header.
+	ChunkSize int `json:"chunk_size" yaml:"chunk_size" mapstructure:"chunk_size"`
+	// Time allowed to dial and write a message.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Minimum redial backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum redial backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+	// Additional fields added to every message, e.g. app or env.
+	Fields map[string]string `json:"fields" yaml:"fields" mapstructure:"fields"`
+
+	// entry parser by the write encoder config.
+	parser entry.Parser
+
+	mutex   sync.Mutex
+	conn    net.Conn
+	closed  bool
+	backoff backoff.Backoff
+	// redial is not allowed before retry time.
+	retry time.Time
+	// last error.
+	err error
+}
+
+// New logger with network and address.
+func New(network, address string) *Logger {
+	l := GetDefault()
+	l.Network = network
+	l.Address = address
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		Network:     Network,
+		Address:     Address,
+		Compression: Gzip,
+		ChunkSize:   ChunkSize,
+		Timeout:     Timeout,
+		MinBackoff:  backoff.DefaultMin,
+		MaxBackoff:  backoff.DefaultMax,
+	}
+}
+
+// Implement Cloner interface, only the config is copied, the clone sends by its own connection.
+func (l *Logger) Clone() io.Writer {
+	n := &Logger{
+		Network:     l.Network,
+		Address:     l.Address,
+		Host:        l.Host,
+		Compression: l.Compression,
+		ChunkSize:   l.ChunkSize,
+		Timeout:     l.Timeout,
+		MinBackoff:  l.MinBackoff,
+		MaxBackoff:  l.MaxBackoff,
+	}
+	if l.Fields != nil {
+		n.Fields = make(map[string]string, len(l.Fields))
+		for k, v := range l.Fields {
+			n.Fields[k] = v
+		}
+	}
+	return n
+}
+
+// Implement syncer EntryParser interface, entries are parsed as json.
+func (l *Logger) Encoding() string {
+	return zapEncoder.JSON
+}
+
+// Implement syncer EntryParser interface.
+func (l *Logger) SetEncoderConfig(config zapcore.EncoderConfig) {
+	l.parser.SetEncoderConfig(config)
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	switch l.Network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6":
+	default:
+		errs = append(errs, fmt.Errorf("network: not support %q", l.Network))
+	}
+	if l.Address == "" {
+		errs = append(errs, errors.New("address: must not be empty"))
+	}
+	switch l.Compression {
+	case Gzip, Zlib, None:
+	default:
+		errs = append(errs, fmt.Errorf("compression: should be %s, %s or %s", Gzip, Zlib, None))
+	}
+	if l.ChunkSize <= chunkHeaderSize {
+		errs = append(errs, fmt.Errorf("chunk_size: must be greater than %d", chunkHeaderSize))
+	}
+	if l.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout: must be positive"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+	for k := range l.Fields {
+		if field(k) != "_"+k {
+			errs = append(errs, fmt.Errorf("fields: invalid field name %q", k))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return errors.New("gelf logger is closed")
+	}
+
+	return l.err
+}
+
+// Implement Writer interface, p should be a zap json entry.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	message, err := l.message(p)
+	if err != nil {
+		return 0, err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return 0, errors.New("gelf logger is closed")
+	}
+
+	if l.conn == nil {
+		if time.Now().Before(l.retry) {
+			return 0, l.err
+		}
+		if err := l.dial(); err != nil {
+			return 0, l.fail(err)
+		}
+	}
+
+	if err := l.send(message); err != nil {
+		// the connection may be closed by the server, redial and send once more.
+		l.conn.Close()
+		l.conn = nil
+		if err = l.dial(); err == nil {
+			err = l.send(message)
+		}
+		if err != nil {
+			return 0, l.fail(err)
+		}
+	}
+
+	l.backoff.Reset()
+	l.err = nil
+
+	return len(p), nil
+}
+
+// Implement WriteSyncer interface, messages are sent by Write.
+func (l *Logger) Sync() error {
+	return nil
+}
+
+// Close connection.
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return errors.New("gelf logger is already closed")
+	}
+	l.closed = true
+
+	if l.conn == nil {
+		return nil
+	}
+
+	err := l.conn.Close()
+	l.conn = nil
+
+	return err
+}
+
+// record error and close the connection, redial is allowed after backoff.
+func (l *Logger) fail(err error) error {
+	if l.conn != nil {
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	l.backoff.Min, l.backoff.Max = l.MinBackoff, l.MaxBackoff
+	l.retry = time.Now().Add(l.backoff.Next())
+	l.err = err
+
+	return err
+}
+
+// get timeout.
+func (l *Logger) timeout() time.Duration {
+	if l.Timeout > 0 {
+		return l.Timeout
+	}
+	return Timeout
+}
+
+// is network udp.
+func (l *Logger) udp() bool {
+	switch l.Network {
+	case "udp", "udp4", "udp6":
+		return true
+	}
+	return false
+}
+
+// dial graylog input, should be called with lock.
+func (l *Logger) dial() error {
+	conn, err := net.DialTimeout(l.Network, l.Address, l.timeout())
+	if err != nil {
+		return err
+	}
+	l.conn = conn
+	return nil
+}
+
+// send message, udp message is compressed and chunked, tcp message is null terminated.
+// should be called with lock.
+func (l *Logger) send(message []byte) error {
+	if err := l.conn.SetWriteDeadline(time.Now().Add(l.timeout())); err != nil {
+		return err
+	}
+
+	if !l.udp() {
+		_, err := l.conn.Write(append(message, 0))
+		return err
+	}
+
+	message, err := l.compress(message)
+	if err != nil {
+		return err
+	}
+
+	chunkSize := l.ChunkSize
+	if chunkSize <= chunkHeaderSize {
+		chunkSize = ChunkSize
+	}
+	if len(message) <= chunkSize {
+		_, err := l.conn.Write(message)
+		return err
+	}
+
+	// chunked message.
+	size := chunkSize - chunkHeaderSize
+	count := (len(message) + size - 1) / size
+	if count > maxChunks {
+		return fmt.Errorf("message of %d bytes is too large for %d chunks", len(message), maxChunks)
+	}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return err
+	}
+
+	chunk := make([]byte, 0, chunkSize)
+	for i := 0; i < count; i++ {
+		end := (i + 1) * size
+		if end > len(message) {
+			end = len(message)
+		}
+
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, message[i*size:end]...)
+
+		if _, err := l.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// compress message.
+func (l *Logger) compress(message []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var w io.WriteCloser
+
+	switch l.Compression {
+	case None:
+		return message, nil
+	case Zlib:
+		w = zlib.NewWriter(&buf)
+	default:
+		w = gzip.NewWriter(&buf)
+	}
+
+	if _, err := w.Write(message); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Invalid characters of additional field name.
+var invalidField = regexp.MustCompile(`[^\w.\-]`)
+
+// get additional field name with _ prefix, invalid characters are replaced by _, _id is not allowed.
+func field(name string) string {
+	name = invalidField.ReplaceAllString(name, "_")
+	if name == "id" {
+		return "_id_"
+	}
+	return "_" + name
+}
+
+// get additional field value, strings and numbers are kept, others are json encoded.
+func value(v interface{}) interface{} {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v
+	}
+
+	if s, err := encoder.MarshalToString(v); err == nil {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// format GELF message of entry.
+func (l *Logger) message(p []byte) ([]byte, error) {
+	e := l.parser.Parse(p)
+
+	host := l.Host
+	if host == "" {
+		host = hostname
+	}
+
+	message := make(map[string]interface{}, len(e.Fields)+len(l.Fields)+8)
+	for k, v := range l.Fields {
+		message[field(k)] = v
+	}
+	for k, v := range e.Fields {
+		message[field(k)] = value(v)
+	}
+
+	message["version"] = "1.1"
+	message["host"] = host
+	message["short_message"] = e.Message
+	if e.Message == "" {
+		message["short_message"] = "-"
+	}
+	if e.Stack != "" {
+		message["full_message"] = e.Stack
+	}
+	message["timestamp"] = json.Number(fmt.Sprintf("%d.%06d", e.Time.Unix(), e.Time.Nanosecond()/1000))
+	message["level"] = syslog.Severity(e.Level)
+	if e.Logger != "" {
+		message["_logger"] = e.Logger
+	}
+	if e.Caller != "" {
+		message["_caller"] = e.Caller
+	}
+
+	return encoder.Marshal(message)
+}
@@ -0,0 +1,312 @@
+package gelf_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	zapConfig "github.com/go-framework/zap"
+	"github.com/go-framework/zap/syncer"
+	"github.com/go-framework/zap/syncer/gelf"
+)
+
+// read udp GELF message, chunks are reassembled and the message is decompressed.
+func readUDP(t *testing.T, conn net.PacketConn) map[string]interface{} {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var id, message []byte
+	var chunks [][]byte
+	buf := make([]byte, 65536)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := append([]byte(nil), buf[:n]...)
+
+		if len(p) < 2 || p[0] != 0x1e || p[1] != 0x0f {
+			message = p
+			break
+		}
+
+		// chunk header: magic, id, sequence number and count.
+		seq, count := int(p[10]), int(p[11])
+		if chunks == nil {
+			id, chunks = p[2:10], make([][]byte, count)
+		}
+		if len(chunks) != count || seq >= count || !bytes.Equal(p[2:10], id) {
+			t.Fatalf("unexpected chunk %v", p[:12])
+		}
+		chunks[seq] = p
+
+		complete := true
+		for _, chunk := range chunks {
+			complete = complete && chunk != nil
+		}
+		if complete {
+			for _, chunk := range chunks {
+				message = append(message, chunk[12:]...)
+			}
+			break
+		}
+	}
+
+	var reader io.Reader
+	var err error
+	switch {
+	case bytes.HasPrefix(message, []byte{0x1f, 0x8b}):
+		reader, err = gzip.NewReader(bytes.NewReader(message))
+	case message[0] == 0x78:
+		reader, err = zlib.NewReader(bytes.NewReader(message))
+	default:
+		reader = bytes.NewReader(message)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make(map[string]interface{})
+	if err := jsoniter.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("%v: %s", err, data)
+	}
+	return fields
+}
+
+func TestLogger_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	data := `
+level: info
+writes:
+  - name: gelf
+    config:
+      address: ` + conn.LocalAddr().String() + `
+      host: test-host
+      chunk_size: 64
+      fields:
+        app: test
+`
+	config := &zapConfig.Config{}
+	if err := yaml.Unmarshal([]byte(data), config); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := config.NewZapLogger()
+	defer config.Close()
+
+	logger.Named("db").Warn("first", zap.Int("count", 1), zap.Bool("ok", true), zap.String("id", "x"), zap.String("user name", "alice"))
+
+	message := readUDP(t, conn)
+	if message["version"] != "1.1" || message["host"] != "test-host" || message["short_message"] != "first" || message["level"] != float64(4) {
+		t.Errorf("unexpected message %v", message)
+	}
+	if message["_logger"] != "db" || message["_caller"] == nil || message["_app"] != "test" || message["full_message"] != nil {
+		t.Errorf("unexpected message %v", message)
+	}
+	if message["_count"] != float64(1) || message["_ok"] != "true" || message["_id_"] != "x" || message["_user_name"] != "alice" {
+		t.Errorf("unexpected message fields %v", message)
+	}
+	if ts, ok := message["timestamp"].(float64); !ok || time.Since(time.Unix(int64(ts), 0)) > time.Minute {
+		t.Errorf("unexpected timestamp %v", message["timestamp"])
+	}
+
+	// stacktrace of error entry makes the message chunked.
+	logger.Error("second")
+
+	message = readUDP(t, conn)
+	if message["short_message"] != "second" || message["level"] != float64(3) || !strings.Contains(message["full_message"].(string), "gelf_test") {
+		t.Errorf("unexpected message %v", message)
+	}
+}
+
+func TestLogger_Development(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// development encoder keys and colored levels, the write encoding defaults to json.
+	data := `
+level: debug
+development: true
+encoder:
+  level_encoder: capitalColor
+writes:
+  - name: gelf
+    config:
+      address: ` + conn.LocalAddr().String() + `
+`
+	config := &zapConfig.Config{}
+	if err := yaml.Unmarshal([]byte(data), config); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := config.NewZapLogger()
+	defer config.Close()
+
+	logger.Named("db").Warn("development", zap.Int("count", 1))
+
+	message := readUDP(t, conn)
+	if message["short_message"] != "development" || message["level"] != float64(4) || message["_logger"] != "db" || message["_count"] != float64(1) {
+		t.Errorf("unexpected message %v", message)
+	}
+	if message["_M"] != nil || message["_L"] != nil || message["_T"] != nil {
+		t.Errorf("unexpected message fields %v", message)
+	}
+}
+
+func TestLogger_Zlib(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for _, compression := range []string{gelf.Zlib, gelf.None} {
+		l := gelf.New("udp", conn.LocalAddr().String())
+		l.Compression = compression
+
+		if _, err := l.Write([]byte(`{"level":"debug","ts":1600000000.25,"msg":"compressed","nested":{"a":1}}` + "\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		message := readUDP(t, conn)
+		if message["short_message"] != "compressed" || message["level"] != float64(7) || message["_nested"] != `{"a":1}` || message["timestamp"] != 1600000000.25 {
+			t.Errorf("%s: unexpected message %v", compression, message)
+		}
+		l.Close()
+	}
+}
+
+func TestLogger_TCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	messages := make(chan string, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			for {
+				message, err := reader.ReadString(0)
+				if err != nil {
+					conn.Close()
+					break
+				}
+				messages <- message
+			}
+		}
+	}()
+
+	l := gelf.New("tcp", listener.Addr().String())
+	l.Host = "test-host"
+	defer l.Close()
+
+	for _, line := range []string{`{"level":"info","ts":1600000000,"msg":"first"}`, "plain text"} {
+		if _, err := l.Write([]byte(line + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case message := <-messages:
+		if message != `{"host":"test-host","level":6,"short_message":"first","timestamp":1600000000.000000,"version":"1.1"}`+"\x00" {
+			t.Errorf("unexpected message %q", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not received")
+	}
+	select {
+	case message := <-messages:
+		if !strings.Contains(message, `"short_message":"plain text"`) {
+			t.Errorf("unexpected message %q", message)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message is not received")
+	}
+}
+
+func TestLogger_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	l := gelf.New("tcp", address)
+	l.MinBackoff = time.Hour
+	l.MaxBackoff = time.Hour
+	defer l.Close()
+
+	if _, err := l.Write([]byte(`{"msg":"lost"}`)); err == nil {
+		t.Fatal("write should fail")
+	}
+	if l.Health() == nil {
+		t.Error("logger should be unhealthy")
+	}
+
+	// fail fast before backoff.
+	start := time.Now()
+	if _, err := l.Write([]byte(`{"msg":"lost"}`)); err == nil || time.Since(start) > time.Second {
+		t.Errorf("write should fail fast: %v", err)
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	writer := &gelf.Logger{Network: "unix", Compression: "lz4", ChunkSize: 8, MinBackoff: time.Second, Fields: map[string]string{"bad name": "x"}}
+
+	err := (&syncer.Write{Name: gelf.Name, Config: writer}).Validate()
+	if err == nil {
+		t.Fatal("logger should be invalid")
+	}
+	for _, expect := range []string{"network", "address", "compression", "chunk_size", "timeout", "max_backoff", "fields"} {
+		if !strings.Contains(err.Error(), expect+":") {
+			t.Errorf("error should contain %s: %v", expect, err)
+		}
+	}
+
+	// entries are parsed as json.
+	err = (&syncer.Write{Name: gelf.Name, Encoding: "console", Config: gelf.GetDefault()}).Validate()
+	if err == nil || !strings.Contains(err.Error(), "encoding:") {
+		t.Errorf("console encoding should be invalid: %v", err)
+	}
+
+	if err := gelf.GetDefault().Validate(); err != nil {
+		t.Errorf("default logger should be valid: %v", err)
+	}
+}
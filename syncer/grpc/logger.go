@@ -0,0 +1,388 @@
+package grpc
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	"github.com/go-framework/zap/collector"
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/batch"
+	"github.com/go-framework/zap/syncer/internal/entry"
+)
+
+const (
+	// Name.
+	Name = "grpc"
+
+	// Max entries in one push.
+	BatchSize = 100
+	// Push interval of the pending entries.
+	FlushInterval = time.Second
+	// The max amount of buffered entries, entries are dropped when the buffer is full.
+	BufferSize = 10000
+	// Time allowed to push a batch, sync and close.
+	Timeout = 10 * time.Second
+)
+
+// Grpc logger pushes the zap json entries to the LogCollector service in batches,
+// a batch is retried with backoff until the collector acknowledges it.
+type Logger struct {
+	// Collector address as host:port.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Connect without TLS.
+	Insecure bool `json:"insecure" yaml:"insecure" mapstructure:"insecure"`
+	// TLS CA certificate file, default is the system pool.
+	CA string `json:"ca" yaml:"ca" mapstructure:"ca"`
+	// TLS server name, default is the host of address.
+	ServerName string `json:"server_name" yaml:"server_name" mapstructure:"server_name"`
+	// TLS skip server certificate verification.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
+	// Max entries in one push.
+	BatchSize int `json:"batch_size" yaml:"batch_size" mapstructure:"batch_size"`
+	// Push interval of the pending entries.
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval" mapstructure:"flush_interval"`
+	// The max amount of buffered entries.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size" mapstructure:"buffer_size"`
+	// Time allowed to push a batch, sync and close.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Minimum retry backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum retry backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+	// Fields added to every entry, e.g. host or service.
+	Fields map[string]string `json:"fields" yaml:"fields" mapstructure:"fields"`
+
+	// entry parser by the write encoder config.
+	parser entry.Parser
+
+	once  sync.Once
+	loop  batch.Loop
+	mutex sync.RWMutex
+	conn  *grpc.ClientConn
+}
+
+// New logger with collector address.
+func New(address string) *Logger {
+	l := GetDefault()
+	l.Address = address
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		BatchSize:     BatchSize,
+		FlushInterval: FlushInterval,
+		BufferSize:    BufferSize,
+		Timeout:       Timeout,
+		MinBackoff:    backoff.DefaultMin,
+		MaxBackoff:    backoff.DefaultMax,
+	}
+}
+
+// Implement Cloner interface, only the config is copied, the clone pushes by its own connection.
+func (l *Logger) Clone() io.Writer {
+	n := &Logger{
+		Address:            l.Address,
+		Insecure:           l.Insecure,
+		CA:                 l.CA,
+		ServerName:         l.ServerName,
+		InsecureSkipVerify: l.InsecureSkipVerify,
+		BatchSize:          l.BatchSize,
+		FlushInterval:      l.FlushInterval,
+		BufferSize:         l.BufferSize,
+		Timeout:            l.Timeout,
+		MinBackoff:         l.MinBackoff,
+		MaxBackoff:         l.MaxBackoff,
+	}
+	if l.Fields != nil {
+		n.Fields = make(map[string]string, len(l.Fields))
+		for k, v := range l.Fields {
+			n.Fields[k] = v
+		}
+	}
+	return n
+}
+
+// Implement syncer EntryParser interface, entries are parsed as json.
+func (l *Logger) Encoding() string {
+	return encoder.JSON
+}
+
+// Implement syncer EntryParser interface.
+func (l *Logger) SetEncoderConfig(config zapcore.EncoderConfig) {
+	l.parser.SetEncoderConfig(config)
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	if l.Address == "" {
+		errs = append(errs, errors.New("address: must not be empty"))
+	}
+	if l.BatchSize <= 0 {
+		errs = append(errs, errors.New("batch_size: must be positive"))
+	}
+	if l.FlushInterval <= 0 {
+		errs = append(errs, errors.New("flush_interval: must be positive"))
+	}
+	if l.BufferSize <= 0 {
+		errs = append(errs, errors.New("buffer_size: must be positive"))
+	}
+	if l.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout: must be positive"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	return l.loop.Health(Name)
+}
+
+// Get dropped entries count.
+func (l *Logger) Dropped() uint64 {
+	return l.loop.Dropped()
+}
+
+// Implement Writer interface, p should be a zap json entry.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	l.once.Do(l.start)
+
+	if err := l.loop.Add(l.newEntry(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Implement WriteSyncer interface, push the buffered entries.
+func (l *Logger) Sync() error {
+	return l.loop.Sync()
+}
+
+// Close, push the buffered entries and close the connection.
+func (l *Logger) Close() error {
+	l.once.Do(func() {})
+
+	err := l.loop.Close(Name)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn != nil {
+		err = multierr.Append(err, l.conn.Close())
+	}
+
+	return err
+}
+
+// get timeout.
+func (l *Logger) timeout() time.Duration {
+	if l.Timeout > 0 {
+		return l.Timeout
+	}
+	return Timeout
+}
+
+// start push loop.
+func (l *Logger) start() {
+	size, batchSize := l.BufferSize, l.BatchSize
+	if size <= 0 {
+		size = BufferSize
+	}
+	if batchSize <= 0 {
+		batchSize = BatchSize
+	}
+	interval := l.FlushInterval
+	if interval <= 0 {
+		interval = FlushInterval
+	}
+
+	l.loop.Start(batch.Config{
+		Name:          Name,
+		BufferSize:    size,
+		BatchSize:     batchSize,
+		FlushInterval: interval,
+		Timeout:       l.timeout(),
+		MinBackoff:    l.MinBackoff,
+		MaxBackoff:    l.MaxBackoff,
+		Push: func(entries []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+			return l.push(entries, batchSize, b, exit)
+		},
+	})
+}
+
+// new log entry from zap json entry.
+func (l *Logger) newEntry(p []byte) *collector.LogEntry {
+	e := l.parser.Parse(p)
+
+	level, err := collector.LevelFromZap(e.Level)
+	if err != nil {
+		level = collector.Level_Info
+	}
+
+	fields := collector.EncodeFields(e.Fields)
+	if len(l.Fields) > 0 && fields == nil {
+		fields = make(map[string]string, len(l.Fields))
+	}
+	for k, v := range l.Fields {
+		if _, ok := fields[k]; !ok {
+			fields[k], _ = jsoniter.MarshalToString(v)
+		}
+	}
+
+	return &collector.LogEntry{
+		Time:    e.Time.UnixNano(),
+		Level:   level,
+		Logger:  e.Logger,
+		Caller:  e.Caller,
+		Message: e.Message,
+		Stack:   e.Stack,
+		Fields:  fields,
+	}
+}
+
+// push batch in chunks of batch size, retry with backoff until acknowledged or exit closed,
+// return the entries which are not pushed. exit nil pushes once without retry.
+func (l *Logger) push(entries []interface{}, batchSize int, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+	for len(entries) > 0 {
+		n := len(entries)
+		if n > batchSize {
+			n = batchSize
+		}
+
+		chunk := make([]*collector.LogEntry, n)
+		for i := range chunk {
+			chunk[i] = entries[i].(*collector.LogEntry)
+		}
+
+		count, err := l.send(chunk)
+		entries = entries[count:]
+		l.loop.SetError(err)
+
+		if err == nil {
+			b.Reset()
+			continue
+		}
+		if exit == nil || !b.Sleep(exit) {
+			break
+		}
+	}
+
+	// reuse the batch buffer.
+	if len(entries) == 0 {
+		return entries[:0]
+	}
+
+	return entries
+}
+
+// send entries in one push stream, return the acknowledged entries count.
+func (l *Logger) send(entries []*collector.LogEntry) (int, error) {
+	conn, err := l.dial()
+	if err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), l.timeout())
+	defer cancel()
+
+	stream, err := collector.NewLogCollectorClient(conn).Push(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, e := range entries {
+		if err := stream.Send(e); err != nil {
+			// the error of send is returned by CloseAndRecv.
+			break
+		}
+	}
+
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, err
+	}
+
+	count := int(resp.Count)
+	if count > len(entries) {
+		count = len(entries)
+	}
+	if count < len(entries) {
+		return count, fmt.Errorf("collector acknowledged %d of %d entries", count, len(entries))
+	}
+
+	return count, nil
+}
+
+// dial collector, the connection reconnects by itself.
+func (l *Logger) dial() (*grpc.ClientConn, error) {
+	l.mutex.RLock()
+	conn := l.conn
+	l.mutex.RUnlock()
+	if conn != nil {
+		return conn, nil
+	}
+
+	opts := []grpc.DialOption{grpc.WithInsecure()}
+	if !l.Insecure {
+		config, err := l.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(config))}
+	}
+
+	conn, err := grpc.Dial(l.Address, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mutex.Lock()
+	l.conn = conn
+	l.mutex.Unlock()
+
+	return conn, nil
+}
+
+// get tls config.
+func (l *Logger) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         l.ServerName,
+		InsecureSkipVerify: l.InsecureSkipVerify,
+	}
+
+	if l.CA != "" {
+		data, err := ioutil.ReadFile(l.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca: no certificate found in %s", l.CA)
+		}
+		config.RootCAs = pool
+	}
+
+	return config, nil
+}
@@ -0,0 +1,163 @@
+package grpc_test
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v2"
+
+	zapConfig "github.com/go-framework/zap"
+	"github.com/go-framework/zap/collector"
+	"github.com/go-framework/zap/syncer"
+	grpcSyncer "github.com/go-framework/zap/syncer/grpc"
+)
+
+// serve collector on listener, received entries are observed.
+func serveCollector(t *testing.T, listener net.Listener) (*grpc.Server, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	server := grpc.NewServer()
+	collector.RegisterLogCollectorServer(server, collector.NewServer(zap.New(core)).SetPeerKey("peer"))
+	go server.Serve(listener)
+
+	return server, logs
+}
+
+func TestLogger_Push(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server, logs := serveCollector(t, listener)
+	defer server.Stop()
+
+	data := `
+level: info
+writes:
+  - name: grpc
+    level: warn
+    config:
+      address: ` + listener.Addr().String() + `
+      insecure: true
+      batch_size: 2
+      fields:
+        host: test
+`
+	config := &zapConfig.Config{}
+	if err := yaml.Unmarshal([]byte(data), config); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger := config.NewZapLogger()
+	logger.Named("db").Warn("first", zap.Int("count", 1), zap.String("user", "alice"),
+		zap.String("id", "123"), zap.String("offset", "-1"), zap.String("flag", "true"), zap.String("code", "007"), zap.Bool("ok", true))
+	logger.Info("filtered")
+	logger.Error("second")
+	logger.Warn("third")
+
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := logs.AllUntimed()
+	if len(entries) != 3 {
+		t.Fatalf("expect 3 entries, got %v", entries)
+	}
+
+	first := entries[0]
+	if first.Message != "first" || first.LoggerName != "db" || first.Level != zap.WarnLevel || !first.Caller.Defined {
+		t.Errorf("unexpected entry %v", first)
+	}
+	fields := first.ContextMap()
+	if fields["count"] != float64(1) || fields["user"] != "alice" || fields["host"] != "test" || fields["peer"] == nil {
+		t.Errorf("unexpected entry fields %v", fields)
+	}
+	// the string values which look like json are kept as strings.
+	if fields["id"] != "123" || fields["offset"] != "-1" || fields["flag"] != "true" || fields["code"] != "007" || fields["ok"] != true {
+		t.Errorf("field types should be kept %v", fields)
+	}
+	if entries[1].Message != "second" || entries[1].Level != zap.ErrorLevel || entries[1].Stack == "" {
+		t.Errorf("unexpected entry %v", entries[1])
+	}
+}
+
+func TestLogger_Reconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	writer := grpcSyncer.New(address)
+	writer.Insecure = true
+	writer.Timeout = 500 * time.Millisecond
+	writer.MinBackoff = 10 * time.Millisecond
+	writer.MaxBackoff = 50 * time.Millisecond
+
+	config := &zapConfig.Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	config.AddSyncerWrite(&syncer.Write{Name: grpcSyncer.Name, Config: writer})
+
+	logger := config.NewZapLogger()
+	logger.Info("buffered")
+
+	// collector is down.
+	if err := logger.Sync(); err == nil {
+		t.Fatal("expect sync error when collector is down")
+	}
+	if err := writer.Health(); err == nil {
+		t.Error("expect unhealthy when collector is down")
+	}
+
+	// collector is up.
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skip(err)
+	}
+	server, logs := serveCollector(t, listener)
+	defer server.Stop()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for logs.Len() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expect entry pushed after reconnect")
+		}
+		_ = logger.Sync()
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if entries := logs.AllUntimed(); entries[0].Message != "buffered" {
+		t.Errorf("unexpected entries %v", entries)
+	}
+	if err := writer.Health(); err != nil {
+		t.Error(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Error(err)
+	}
+	if _, err := writer.Write([]byte("{}")); err == nil {
+		t.Error("expect write error after close")
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	writer := grpcSyncer.GetDefault()
+	writer.BatchSize = 0
+
+	err := (&syncer.Write{Name: grpcSyncer.Name, Config: writer}).Validate()
+	if err == nil {
+		t.Fatal("expect validate error")
+	}
+	t.Log(err)
+}
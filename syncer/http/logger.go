@@ -0,0 +1,662 @@
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/batch"
+	"github.com/go-framework/zap/syncer/internal/entry"
+	"github.com/go-framework/zap/syncer/internal/redact"
+)
+
+const (
+	// Name.
+	Name = "http"
+
+	// Max entries in one request.
+	BatchSize = 100
+	// Max body bytes of entries in one request before compression.
+	BatchBytes = 1 << 20
+	// Send interval of the pending entries.
+	FlushInterval = time.Second
+	// The max amount of buffered entries, entries are dropped when the buffer is full.
+	BufferSize = 10000
+	// Time allowed to send a request, sync and close.
+	Timeout = 10 * time.Second
+	// Retries of a batch before it is dropped.
+	MaxRetries = 5
+)
+
+// Modes.
+const (
+	// Generic mode, entries are posted as newline delimited json.
+	Generic = "generic"
+	// Loki push api mode, entries are grouped into streams by labels.
+	Loki = "loki"
+	// Elasticsearch bulk api mode, entries are indexed by the index template.
+	Elasticsearch = "elasticsearch"
+)
+
+// Loki push api path.
+const lokiPath = "/loki/api/v1/push"
+
+// Http logger posts the zap json entries in batches, a batch is retried with backoff
+// on network errors, 429 and 5xx responses until max retries then dropped.
+type Logger struct {
+	// Mode: generic, loki or elasticsearch.
+	Mode string `json:"mode" yaml:"mode" mapstructure:"mode"`
+	// Url, the loki push path and the elasticsearch bulk path are appended when the url has no path.
+	URL string `json:"url" yaml:"url" mapstructure:"url"`
+	// Request headers, e.g. X-Scope-OrgID of loki.
+	Headers map[string]string `json:"headers" yaml:"headers" mapstructure:"headers"`
+	// Basic auth username.
+	Username string `json:"username" yaml:"username" mapstructure:"username"`
+	// Basic auth password.
+	Password string `json:"password" yaml:"password" mapstructure:"password"`
+	// Compress request body with gzip.
+	Gzip bool `json:"gzip" yaml:"gzip" mapstructure:"gzip"`
+	// Loki static stream labels, e.g. job: app.
+	Labels map[string]string `json:"labels" yaml:"labels" mapstructure:"labels"`
+	// Loki label fields, level, logger or entry field names, e.g. [level, logger], default is [level].
+	LabelFields []string `json:"label_fields" yaml:"label_fields" mapstructure:"label_fields"`
+	// Elasticsearch index template, the time layout in braces is formatted by the entry time in UTC,
+	// e.g. logs-{2006.01.02}.
+	Index string `json:"index" yaml:"index" mapstructure:"index"`
+	// Elasticsearch bulk action: index or create, create is required by data streams.
+	Action string `json:"action" yaml:"action" mapstructure:"action"`
+	// Max entries in one request.
+	BatchSize int `json:"batch_size" yaml:"batch_size" mapstructure:"batch_size"`
+	// Max body bytes of entries in one request before compression.
+	BatchBytes int `json:"batch_bytes" yaml:"batch_bytes" mapstructure:"batch_bytes"`
+	// Send interval of the pending entries.
+	FlushInterval time.Duration `json:"flush_interval" yaml:"flush_interval" mapstructure:"flush_interval"`
+	// The max amount of buffered entries.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size" mapstructure:"buffer_size"`
+	// Time allowed to send a request, sync and close.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Retries of a batch before it is dropped.
+	MaxRetries int `json:"max_retries" yaml:"max_retries" mapstructure:"max_retries"`
+	// Minimum retry backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum retry backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+
+	// entry parser by the write encoder config.
+	parser entry.Parser
+
+	once   sync.Once
+	loop   batch.Loop
+	client *http.Client
+}
+
+// Buffered entry.
+type item struct {
+	// entry line without line ending.
+	line []byte
+	// parsed entry, nil in generic mode.
+	entry *entry.Entry
+}
+
+// New logger with mode and url.
+func New(mode, url string) *Logger {
+	l := GetDefault()
+	l.Mode = mode
+	l.URL = url
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		Mode:          Generic,
+		LabelFields:   []string{"level"},
+		Index:         "zap-{2006.01.02}",
+		Action:        "index",
+		BatchSize:     BatchSize,
+		BatchBytes:    BatchBytes,
+		FlushInterval: FlushInterval,
+		BufferSize:    BufferSize,
+		Timeout:       Timeout,
+		MaxRetries:    MaxRetries,
+		MinBackoff:    backoff.DefaultMin,
+		MaxBackoff:    backoff.DefaultMax,
+	}
+}
+
+// copy string map.
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	n := make(map[string]string, len(m))
+	for k, v := range m {
+		n[k] = v
+	}
+	return n
+}
+
+// Implement Cloner interface, only the config is copied.
+func (l *Logger) Clone() io.Writer {
+	n := &Logger{
+		Mode:          l.Mode,
+		URL:           l.URL,
+		Headers:       copyMap(l.Headers),
+		Username:      l.Username,
+		Password:      l.Password,
+		Gzip:          l.Gzip,
+		Labels:        copyMap(l.Labels),
+		Index:         l.Index,
+		Action:        l.Action,
+		BatchSize:     l.BatchSize,
+		BatchBytes:    l.BatchBytes,
+		FlushInterval: l.FlushInterval,
+		BufferSize:    l.BufferSize,
+		Timeout:       l.Timeout,
+		MaxRetries:    l.MaxRetries,
+		MinBackoff:    l.MinBackoff,
+		MaxBackoff:    l.MaxBackoff,
+	}
+	if l.LabelFields != nil {
+		n.LabelFields = append([]string(nil), l.LabelFields...)
+	}
+	return n
+}
+
+// Implement syncer Redactor interface, the password and header values are masked.
+func (l *Logger) Redact() interface{} {
+	type config Logger
+
+	return &struct {
+		*config
+		Headers  map[string]string `json:"headers" yaml:"headers"`
+		Password string            `json:"password" yaml:"password"`
+	}{
+		config:   (*config)(l),
+		Headers:  redact.Map(l.Headers),
+		Password: redact.String(l.Password),
+	}
+}
+
+// Implement syncer EntryParser interface, entries are parsed as json.
+func (l *Logger) Encoding() string {
+	return encoder.JSON
+}
+
+// Implement syncer EntryParser interface.
+func (l *Logger) SetEncoderConfig(config zapcore.EncoderConfig) {
+	l.parser.SetEncoderConfig(config)
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	switch l.Mode {
+	case Generic:
+	case Loki:
+		// loki rejects the streams without labels.
+		if len(l.Labels) == 0 && len(l.LabelFields) == 0 {
+			errs = append(errs, errors.New("labels: at least one of labels and label_fields is required in loki mode"))
+		}
+	case Elasticsearch:
+		if l.Index == "" {
+			errs = append(errs, errors.New("index: must not be empty"))
+		}
+		if l.Action != "index" && l.Action != "create" {
+			errs = append(errs, errors.New("action: should be index or create"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("mode: should be %s, %s or %s", Generic, Loki, Elasticsearch))
+	}
+	if !strings.HasPrefix(l.URL, "http://") && !strings.HasPrefix(l.URL, "https://") {
+		errs = append(errs, fmt.Errorf("url: should be http or https url: %q", l.URL))
+	}
+	if l.BatchSize <= 0 {
+		errs = append(errs, errors.New("batch_size: must be positive"))
+	}
+	if l.BatchBytes <= 0 {
+		errs = append(errs, errors.New("batch_bytes: must be positive"))
+	}
+	if l.FlushInterval <= 0 {
+		errs = append(errs, errors.New("flush_interval: must be positive"))
+	}
+	if l.BufferSize <= 0 {
+		errs = append(errs, errors.New("buffer_size: must be positive"))
+	}
+	if l.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout: must be positive"))
+	}
+	if l.MaxRetries < 0 {
+		errs = append(errs, errors.New("max_retries: must not be negative"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	return l.loop.Health(Name)
+}
+
+// Get dropped entries count.
+func (l *Logger) Dropped() uint64 {
+	return l.loop.Dropped()
+}
+
+// Implement Writer interface, p should be a zap json entry.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	l.once.Do(l.start)
+
+	// p is reused by the caller.
+	line := bytes.TrimRight(p, "\r\n")
+	it := &item{line: make([]byte, len(line))}
+	copy(it.line, line)
+	if l.Mode != Generic {
+		it.entry = l.parser.Parse(it.line)
+	}
+
+	if err := l.loop.Add(it); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Implement WriteSyncer interface, send the buffered entries.
+func (l *Logger) Sync() error {
+	return l.loop.Sync()
+}
+
+// Close, send the buffered entries without retry.
+func (l *Logger) Close() error {
+	l.once.Do(func() {})
+
+	err := l.loop.Close(Name)
+
+	if l.client != nil {
+		l.client.CloseIdleConnections()
+	}
+
+	return err
+}
+
+// get timeout.
+func (l *Logger) timeout() time.Duration {
+	if l.Timeout > 0 {
+		return l.Timeout
+	}
+	return Timeout
+}
+
+// start send loop.
+func (l *Logger) start() {
+	size := l.BufferSize
+	if size <= 0 {
+		size = BufferSize
+	}
+	interval := l.FlushInterval
+	if interval <= 0 {
+		interval = FlushInterval
+	}
+
+	l.client = &http.Client{Timeout: l.timeout()}
+
+	l.loop.Start(batch.Config{
+		Name:          Name,
+		BufferSize:    size,
+		BatchSize:     l.batchSize(),
+		BatchBytes:    l.batchBytes(),
+		FlushInterval: interval,
+		Timeout:       l.timeout(),
+		MinBackoff:    l.MinBackoff,
+		MaxBackoff:    l.MaxBackoff,
+		Push:          l.push,
+		Bytes: func(e interface{}) int {
+			return len(e.(*item).line) + 1
+		},
+	})
+}
+
+// get batch size.
+func (l *Logger) batchSize() int {
+	if l.BatchSize > 0 {
+		return l.BatchSize
+	}
+	return BatchSize
+}
+
+// get batch bytes.
+func (l *Logger) batchBytes() int {
+	if l.BatchBytes > 0 {
+		return l.BatchBytes
+	}
+	return BatchBytes
+}
+
+// push batch in requests of batch size and bytes, retry with backoff until max retries then drop,
+// return the entries which are not sent when exit is closed. exit nil pushes once without retry.
+func (l *Logger) push(entries []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+	batch := make([]*item, len(entries))
+	for i, e := range entries {
+		batch[i] = e.(*item)
+	}
+
+	retries := 0
+
+	for len(batch) > 0 {
+		// entries of one request.
+		n, size := 0, 0
+		for n < len(batch) && n < l.batchSize() && (n == 0 || size+len(batch[n].line)+1 <= l.batchBytes()) {
+			size += len(batch[n].line) + 1
+			n++
+		}
+
+		retry, err := l.send(batch[:n])
+		l.loop.SetError(err)
+
+		rest := batch[n:]
+		if len(retry) == 0 {
+			b.Reset()
+			retries = 0
+			batch = rest
+			continue
+		}
+
+		retries++
+		if retries > l.MaxRetries {
+			l.loop.Drop(len(retry))
+			b.Reset()
+			retries = 0
+			batch = rest
+			continue
+		}
+
+		batch = append(append(make([]*item, 0, len(retry)+len(rest)), retry...), rest...)
+		if exit == nil || !b.Sleep(exit) {
+			break
+		}
+	}
+
+	entries = entries[:0]
+	for _, it := range batch {
+		entries = append(entries, it)
+	}
+
+	return entries
+}
+
+// compress body with gzip.
+func compress(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return nil, fmt.Errorf("gzip: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("gzip: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// get request url of mode.
+func (l *Logger) url() string {
+	path := l.URL
+	if n := strings.Index(path, "://"); n >= 0 {
+		path = path[n+3:]
+	}
+	hasPath := strings.IndexByte(path, '/') >= 0 && !strings.HasSuffix(path, "/")
+
+	switch {
+	case l.Mode == Loki && !hasPath:
+		return strings.TrimSuffix(l.URL, "/") + lokiPath
+	case l.Mode == Elasticsearch && !hasPath:
+		return strings.TrimSuffix(l.URL, "/") + "/_bulk"
+	}
+
+	return l.URL
+}
+
+// send entries in one request, return the entries which should be retried,
+// the entries which are failed permanently are dropped.
+func (l *Logger) send(items []*item) ([]*item, error) {
+	var body []byte
+	var contentType string
+	var err error
+
+	switch l.Mode {
+	case Loki:
+		body, err = l.lokiBody(items)
+		contentType = "application/json"
+	case Elasticsearch:
+		body = l.bulkBody(items)
+		contentType = "application/x-ndjson"
+	default:
+		for _, it := range items {
+			body = append(append(body, it.line...), '\n')
+		}
+		contentType = "application/x-ndjson"
+	}
+	if err != nil {
+		l.loop.Drop(len(items))
+		return nil, err
+	}
+
+	if l.Gzip {
+		if body, err = compress(body); err != nil {
+			l.loop.Drop(len(items))
+			return nil, err
+		}
+	}
+
+	req, err := http.NewRequest(http.MethodPost, l.url(), bytes.NewReader(body))
+	if err != nil {
+		l.loop.Drop(len(items))
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if l.Gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	for k, v := range l.Headers {
+		req.Header.Set(k, v)
+	}
+	if l.Username != "" || l.Password != "" {
+		req.SetBasicAuth(l.Username, l.Password)
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return items, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := ioutil.ReadAll(resp.Body)
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return items, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(limit(data)))
+	case resp.StatusCode >= 300:
+		l.loop.Drop(len(items))
+		return nil, fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(limit(data)))
+	}
+
+	if l.Mode == Elasticsearch {
+		return l.bulkResult(items, data)
+	}
+
+	return nil, nil
+}
+
+// limit error message.
+func limit(data []byte) []byte {
+	if len(data) > 512 {
+		return data[:512]
+	}
+	return data
+}
+
+// Loki push request.
+type lokiRequest struct {
+	Streams []*lokiStream `json:"streams"`
+}
+
+// Loki stream.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// Invalid characters of loki label name.
+var invalidLabel = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// get loki labels of entry.
+func (l *Logger) labels(e *entry.Entry) map[string]string {
+	labels := make(map[string]string, len(l.Labels)+len(l.LabelFields))
+	for k, v := range l.Labels {
+		labels[k] = v
+	}
+
+	for _, field := range l.LabelFields {
+		var value string
+		switch field {
+		case "level":
+			value = e.Level.String()
+		case "logger":
+			value = e.Logger
+		default:
+			v, ok := e.Fields[field]
+			if !ok {
+				continue
+			}
+			if s, ok := v.(string); ok {
+				value = s
+			} else {
+				value = fmt.Sprint(v)
+			}
+		}
+		if value != "" {
+			labels[invalidLabel.ReplaceAllString(field, "_")] = value
+		}
+	}
+
+	// the label fields may be absent in the entry, loki rejects the stream without labels.
+	if len(labels) == 0 {
+		labels["level"] = e.Level.String()
+	}
+
+	return labels
+}
+
+// get loki push body, entries are grouped into streams by labels.
+func (l *Logger) lokiBody(items []*item) ([]byte, error) {
+	req := &lokiRequest{}
+	streams := make(map[string]*lokiStream)
+
+	for _, it := range items {
+		labels := l.labels(it.entry)
+
+		// stream key of sorted labels.
+		keys := make([]string, 0, len(labels))
+		for k := range labels {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var key strings.Builder
+		for _, k := range keys {
+			key.WriteString(k + "=" + labels[k] + ",")
+		}
+
+		stream, ok := streams[key.String()]
+		if !ok {
+			stream = &lokiStream{Stream: labels}
+			streams[key.String()] = stream
+			req.Streams = append(req.Streams, stream)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(it.entry.Time.UnixNano(), 10), string(it.line)})
+	}
+
+	return jsoniter.Marshal(req)
+}
+
+// Elasticsearch index template.
+var indexTemplate = regexp.MustCompile(`\{[^}]+\}`)
+
+// get elasticsearch bulk body.
+func (l *Logger) bulkBody(items []*item) []byte {
+	var body []byte
+	for _, it := range items {
+		t := it.entry.Time.UTC()
+		index := indexTemplate.ReplaceAllStringFunc(l.Index, func(layout string) string {
+			return t.Format(layout[1 : len(layout)-1])
+		})
+
+		action, _ := jsoniter.Marshal(map[string]map[string]string{l.Action: {"_index": index}})
+		body = append(append(body, action...), '\n')
+		body = append(append(body, it.line...), '\n')
+	}
+	return body
+}
+
+// Elasticsearch bulk response.
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int         `json:"status"`
+		Error  interface{} `json:"error"`
+	} `json:"items"`
+}
+
+// get the entries which should be retried of the bulk response, 429 and 5xx items are retried.
+func (l *Logger) bulkResult(items []*item, data []byte) ([]*item, error) {
+	resp := &bulkResponse{}
+	if err := jsoniter.Unmarshal(data, resp); err != nil {
+		return nil, fmt.Errorf("bulk response: %v", err)
+	}
+	if !resp.Errors {
+		return nil, nil
+	}
+
+	var retry []*item
+	var dropped int
+	var last interface{}
+	for i, result := range resp.Items {
+		if i >= len(items) {
+			break
+		}
+		for _, r := range result {
+			switch {
+			case r.Status == http.StatusTooManyRequests || r.Status >= 500:
+				retry = append(retry, items[i])
+			case r.Status >= 300:
+				dropped++
+			default:
+				continue
+			}
+			last = r.Error
+		}
+	}
+	l.loop.Drop(dropped)
+
+	return retry, fmt.Errorf("bulk: %d entries are retried, %d are dropped: %v", len(retry), dropped, last)
+}
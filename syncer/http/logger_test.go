@@ -0,0 +1,388 @@
+package http
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/json-iterator/go"
+)
+
+// Received request.
+type request struct {
+	path    string
+	header  http.Header
+	body    string
+	user    string
+	pass    string
+	hasAuth bool
+}
+
+// Fake server records requests, the responses are returned in order, the last one is repeated.
+type server struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	requests  []*request
+	responses []func(w http.ResponseWriter, r *request)
+	count     int32
+}
+
+// new fake server.
+func newServer(t *testing.T, responses ...func(w http.ResponseWriter, r *request)) *server {
+	s := &server{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reader io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			reader = gz
+		}
+		body, _ := ioutil.ReadAll(reader)
+
+		req := &request{path: r.URL.Path, header: r.Header, body: string(body)}
+		req.user, req.pass, req.hasAuth = r.BasicAuth()
+
+		s.mutex.Lock()
+		s.requests = append(s.requests, req)
+		s.mutex.Unlock()
+
+		n := int(atomic.AddInt32(&s.count, 1)) - 1
+		if len(s.responses) == 0 {
+			return
+		}
+		if n >= len(s.responses) {
+			n = len(s.responses) - 1
+		}
+		s.responses[n](w, req)
+	}))
+	return s
+}
+
+// get received requests.
+func (s *server) received() []*request {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]*request(nil), s.requests...)
+}
+
+// respond with status.
+func status(code int) func(w http.ResponseWriter, r *request) {
+	return func(w http.ResponseWriter, r *request) {
+		w.WriteHeader(code)
+	}
+}
+
+// write zap json entries.
+func write(t *testing.T, l *Logger, lines ...string) {
+	t.Helper()
+
+	for _, line := range lines {
+		if _, err := l.Write([]byte(line + "\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLogger_Generic(t *testing.T) {
+	s := newServer(t)
+	defer s.Close()
+
+	l := New(Generic, s.URL+"/ingest")
+	l.Gzip = true
+	l.Username = "user"
+	l.Password = "secret"
+	l.Headers = map[string]string{"X-Token": "token"}
+	l.BatchBytes = 80
+	defer l.Close()
+
+	lines := []string{
+		`{"level":"info","msg":"first entry"}`,
+		`{"level":"info","msg":"second entry"}`,
+		`{"level":"info","msg":"third entry"}`,
+	}
+	write(t, l, lines...)
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := s.received()
+	// split by batch bytes.
+	if len(requests) != 2 {
+		t.Fatalf("requests: %d", len(requests))
+	}
+
+	r := requests[0]
+	if r.path != "/ingest" || r.header.Get("Content-Type") != "application/x-ndjson" || r.header.Get("X-Token") != "token" {
+		t.Errorf("request: %s %v", r.path, r.header)
+	}
+	if !r.hasAuth || r.user != "user" || r.pass != "secret" {
+		t.Errorf("basic auth: %s %s", r.user, r.pass)
+	}
+	if r.body+requests[1].body != strings.Join(lines, "\n")+"\n" {
+		t.Errorf("body: %q %q", r.body, requests[1].body)
+	}
+}
+
+func TestLogger_Retry(t *testing.T) {
+	s := newServer(t, status(http.StatusServiceUnavailable), status(http.StatusTooManyRequests), status(http.StatusOK))
+	defer s.Close()
+
+	l := New(Generic, s.URL)
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = 10 * time.Millisecond
+	defer l.Close()
+
+	write(t, l, `{"msg":"retried"}`)
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := s.received()
+	if len(requests) != 3 {
+		t.Fatalf("requests: %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.body != `{"msg":"retried"}`+"\n" {
+			t.Errorf("body: %q", r.body)
+		}
+	}
+	if l.Dropped() != 0 || l.Health() != nil {
+		t.Errorf("dropped: %d, health: %v", l.Dropped(), l.Health())
+	}
+}
+
+func TestLogger_Drop(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     int
+		requests int
+	}{
+		// not retried.
+		{"bad request", http.StatusBadRequest, 1},
+		// retried until max retries.
+		{"server error", http.StatusInternalServerError, 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s := newServer(t, status(test.code))
+			defer s.Close()
+
+			l := New(Generic, s.URL)
+			l.MinBackoff = time.Millisecond
+			l.MaxBackoff = time.Millisecond
+			l.MaxRetries = 2
+			defer l.Close()
+
+			write(t, l, `{"msg":"dropped"}`)
+			l.Sync()
+
+			if n := len(s.received()); n != test.requests {
+				t.Errorf("requests: %d, expect: %d", n, test.requests)
+			}
+			if l.Dropped() != 1 {
+				t.Errorf("dropped: %d", l.Dropped())
+			}
+			if l.Health() == nil {
+				t.Error("logger should be unhealthy")
+			}
+		})
+	}
+}
+
+func TestLogger_Loki(t *testing.T) {
+	s := newServer(t, status(http.StatusNoContent))
+	defer s.Close()
+
+	l := New(Loki, s.URL)
+	l.Labels = map[string]string{"job": "app"}
+	l.LabelFields = []string{"level", "logger", "http.method"}
+	defer l.Close()
+
+	write(t, l,
+		`{"level":"info","ts":1600000000.5,"logger":"db","msg":"first"}`,
+		`{"level":"error","ts":1600000001,"logger":"db","msg":"second"}`,
+		`{"level":"info","ts":1600000002,"logger":"db","msg":"third","http.method":"GET"}`,
+		`{"level":"info","ts":1600000003,"logger":"db","msg":"fourth"}`,
+	)
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := s.received()
+	if len(requests) != 1 || requests[0].path != lokiPath {
+		t.Fatalf("requests: %+v", requests)
+	}
+
+	req := &lokiRequest{}
+	if err := jsoniter.UnmarshalFromString(requests[0].body, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Streams) != 3 {
+		t.Fatalf("streams: %s", requests[0].body)
+	}
+
+	stream := req.Streams[0]
+	if stream.Stream["job"] != "app" || stream.Stream["level"] != "info" || stream.Stream["logger"] != "db" || len(stream.Values) != 2 {
+		t.Errorf("stream: %+v", stream)
+	}
+	if stream.Values[0][0] != "1600000000500000000" || !strings.Contains(stream.Values[0][1], `"msg":"first"`) {
+		t.Errorf("values: %v", stream.Values)
+	}
+	if req.Streams[2].Stream["http_method"] != "GET" {
+		t.Errorf("stream: %+v", req.Streams[2])
+	}
+}
+
+func TestLogger_LokiLabels(t *testing.T) {
+	s := newServer(t, status(http.StatusNoContent))
+	defer s.Close()
+
+	// the default label fields are [level].
+	l := New(Loki, s.URL)
+	write(t, l, `{"level":"warn","ts":1600000000,"msg":"default"}`)
+
+	// the absent label fields fall back to level.
+	n := New(Loki, s.URL)
+	n.LabelFields = []string{"logger"}
+	write(t, n, `{"level":"error","ts":1600000000,"msg":"absent"}`)
+
+	for _, logger := range []*Logger{l, n} {
+		if err := logger.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests := s.received()
+	if len(requests) != 2 {
+		t.Fatalf("requests: %+v", requests)
+	}
+	for i, level := range []string{"warn", "error"} {
+		req := &lokiRequest{}
+		if err := jsoniter.UnmarshalFromString(requests[i].body, req); err != nil {
+			t.Fatal(err)
+		}
+		if len(req.Streams) != 1 || len(req.Streams[0].Stream) != 1 || req.Streams[0].Stream["level"] != level {
+			t.Errorf("streams: %s", requests[i].body)
+		}
+	}
+}
+
+func TestLogger_Elasticsearch(t *testing.T) {
+	// the second item is rejected once.
+	s := newServer(t,
+		func(w http.ResponseWriter, r *request) {
+			io.WriteString(w, `{"errors":true,"items":[{"index":{"status":201}},{"index":{"status":429,"error":{"type":"es_rejected_execution_exception"}}},{"index":{"status":400,"error":{"type":"mapper_parsing_exception"}}}]}`)
+		},
+		func(w http.ResponseWriter, r *request) {
+			io.WriteString(w, `{"errors":false,"items":[{"index":{"status":201}}]}`)
+		},
+	)
+	defer s.Close()
+
+	l := New(Elasticsearch, s.URL)
+	l.Index = "logs-{2006.01.02}"
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = 10 * time.Millisecond
+	defer l.Close()
+
+	write(t, l,
+		`{"level":"info","ts":1600000000,"msg":"first"}`,
+		`{"level":"info","ts":1600000000,"msg":"second"}`,
+		`{"level":"info","ts":1600000000,"msg":"bad"}`,
+	)
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := s.received()
+	if len(requests) != 2 {
+		t.Fatalf("requests: %d", len(requests))
+	}
+	if requests[0].path != "/_bulk" {
+		t.Errorf("path: %s", requests[0].path)
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(requests[0].body))
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) != 6 || lines[0] != `{"index":{"_index":"logs-2020.09.13"}}` || lines[1] != `{"level":"info","ts":1600000000,"msg":"first"}` {
+		t.Errorf("bulk body: %q", requests[0].body)
+	}
+
+	// only the rejected item is retried.
+	if !strings.Contains(requests[1].body, `"msg":"second"`) || strings.Count(requests[1].body, "\n") != 2 {
+		t.Errorf("retry body: %q", requests[1].body)
+	}
+	if l.Dropped() != 1 {
+		t.Errorf("dropped: %d", l.Dropped())
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	l := New(Loki, "http://localhost:3100")
+	if err := l.Validate(); err != nil {
+		t.Errorf("logger should be valid: %v", err)
+	}
+	l.LabelFields = nil
+	if err := l.Validate(); err == nil || !strings.Contains(err.Error(), "labels:") {
+		t.Errorf("loki logger without labels should be invalid: %v", err)
+	}
+	l.Labels = map[string]string{"job": "app"}
+	if err := l.Validate(); err != nil {
+		t.Errorf("logger should be valid: %v", err)
+	}
+
+	l = &Logger{Mode: Elasticsearch, URL: "localhost", MaxRetries: -1, MinBackoff: time.Second}
+	err := l.Validate()
+	if err == nil {
+		t.Fatal("logger should be invalid")
+	}
+	for _, expect := range []string{"index", "action", "url", "batch_size", "batch_bytes", "flush_interval", "buffer_size", "timeout", "max_retries", "max_backoff"} {
+		if !strings.Contains(err.Error(), expect+":") {
+			t.Errorf("error should contain %s: %v", expect, err)
+		}
+	}
+}
+
+func TestLogger_Redact(t *testing.T) {
+	l := New(Loki, "http://localhost:3100")
+	l.Headers = map[string]string{"Authorization": "Bearer token"}
+	l.Username = "user"
+	l.Password = "secret"
+
+	data, err := jsoniter.MarshalToString(l.Redact())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(data, "token") || strings.Contains(data, "secret") {
+		t.Errorf("secrets should be masked: %s", data)
+	}
+	for _, expect := range []string{`"Authorization":"******"`, `"password":"******"`, `"username":"user"`, `"mode":"loki"`} {
+		if !strings.Contains(data, expect) {
+			t.Errorf("json should contain %s: %s", expect, data)
+		}
+	}
+
+	// the logger config is not masked.
+	if l.Password != "secret" || l.Headers["Authorization"] != "Bearer token" {
+		t.Errorf("logger is changed: %+v", l)
+	}
+	if data, _ := jsoniter.MarshalToString(l); !strings.Contains(data, `"password":"secret"`) {
+		t.Errorf("logger json should not be masked: %s", data)
+	}
+}
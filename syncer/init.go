@@ -1,14 +1,50 @@
 package syncer
 
 import (
+	"io"
+
+	"github.com/go-framework/zap/syncer/fluent"
+	"github.com/go-framework/zap/syncer/gelf"
+	"github.com/go-framework/zap/syncer/grpc"
+	"github.com/go-framework/zap/syncer/http"
 	"github.com/go-framework/zap/syncer/lumberjack"
+	"github.com/go-framework/zap/syncer/net"
+	"github.com/go-framework/zap/syncer/syslog"
 	"github.com/go-framework/zap/syncer/websocket"
 )
 
 // init for Register Writer.
 func init() {
 	// lumberjack
-	RegisterWriter(lumberjack.Name, lumberjack.GetDefault())
+	MustRegisterFactory(lumberjack.Name, func() io.Writer {
+		return lumberjack.GetDefault()
+	}, "rolling log file with size, age and backups limits")
 	// websocket
-	RegisterWriter(websocket.Name, websocket.GetDefault())
+	MustRegisterFactory(websocket.Name, func() io.Writer {
+		return websocket.GetDefault()
+	}, "websocket relay sender, writes when the relay has visitors")
+	// grpc
+	MustRegisterFactory(grpc.Name, func() io.Writer {
+		return grpc.GetDefault()
+	}, "LogCollector gRPC push with batches, acks and reconnect")
+	// syslog
+	MustRegisterFactory(syslog.Name, func() io.Writer {
+		return syslog.GetDefault()
+	}, "syslog RFC 5424 or RFC 3164 messages over udp, tcp, tls or unix socket")
+	// net
+	MustRegisterFactory(net.Name, func() io.Writer {
+		return net.GetDefault()
+	}, "newline or length-prefixed messages over tcp, udp or unix socket with bounded retries")
+	// fluent
+	MustRegisterFactory(fluent.Name, func() io.Writer {
+		return fluent.GetDefault()
+	}, "fluentd or fluent bit forward protocol with tag template, batches and ack response")
+	// http
+	MustRegisterFactory(http.Name, func() io.Writer {
+		return http.GetDefault()
+	}, "http batches as generic ndjson, loki push or elasticsearch bulk with gzip and retries")
+	// gelf
+	MustRegisterFactory(gelf.Name, func() io.Writer {
+		return gelf.GetDefault()
+	}, "graylog GELF 1.1 messages over chunked and compressed udp or null terminated tcp")
 }
@@ -2,9 +2,47 @@ package syncer
 
 import (
 	"io"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/syncer/internal/redact"
 )
 
 // Clone interface.
 type Cloner interface {
 	Clone() io.Writer
 }
+
+// Validator interface, Validate returns the error which message is prefixed by
+// field name as "field: message", multiple errors are combined by go.uber.org/multierr.
+type Validator interface {
+	Validate() error
+}
+
+// Healther interface, Health returns nil when the writer is able to write.
+type Healther interface {
+	Health() error
+}
+
+// Starter interface, Start starts the writer background work when the logger core is built
+// after the config is validated, it should be safe to be called more than once.
+type Starter interface {
+	Start() error
+}
+
+// Entry parser interface of the writer which parses the encoded entries, SetEncoderConfig is called
+// with the effective encoder config of the write before the writer is used. Encoding returns the
+// encoding which the writer parses, the write encoding defaults to it, empty accepts any encoding.
+type EntryParser interface {
+	Encoding() string
+	SetEncoderConfig(config zapcore.EncoderConfig)
+}
+
+// Masked value of the redacted secrets.
+const Redacted = redact.Redacted
+
+// Redactor interface, Redact returns the view of writer config with the secrets masked as Redacted,
+// the view is served by the admin handler instead of the writer config.
+type Redactor interface {
+	Redact() interface{}
+}
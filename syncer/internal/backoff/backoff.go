@@ -0,0 +1,80 @@
+package backoff
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Default backoff values.
+const (
+	DefaultMin    = 100 * time.Millisecond
+	DefaultMax    = 10 * time.Second
+	DefaultFactor = 2
+)
+
+// Exponential backoff, it is not safe for concurrent use.
+type Backoff struct {
+	// Minimum duration, default is DefaultMin.
+	Min time.Duration
+	// Maximum duration, default is DefaultMax.
+	Max time.Duration
+	// Multiplying factor, default is DefaultFactor.
+	Factor float64
+	// Random jitter fraction of duration in [0, 1].
+	Jitter float64
+
+	attempt int
+}
+
+// Get next backoff duration.
+func (b *Backoff) Next() time.Duration {
+	min, max, factor := b.Min, b.Max, b.Factor
+	if min <= 0 {
+		min = DefaultMin
+	}
+	if max <= 0 {
+		max = DefaultMax
+	}
+	if max < min {
+		max = min
+	}
+	if factor < 1 {
+		factor = DefaultFactor
+	}
+
+	d := float64(min) * math.Pow(factor, float64(b.attempt))
+	if d > float64(max) {
+		d = float64(max)
+	}
+	b.attempt++
+
+	if b.Jitter > 0 {
+		d -= d * b.Jitter * rand.Float64()
+	}
+
+	return time.Duration(d)
+}
+
+// Get attempts since reset.
+func (b *Backoff) Attempt() int {
+	return b.attempt
+}
+
+// Reset backoff.
+func (b *Backoff) Reset() {
+	b.attempt = 0
+}
+
+// Sleep next backoff duration, return false when exit is closed.
+func (b *Backoff) Sleep(exit <-chan struct{}) bool {
+	timer := time.NewTimer(b.Next())
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-exit:
+		return false
+	}
+}
@@ -0,0 +1,315 @@
+package batch
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/go-framework/zap/syncer/internal/backoff"
+)
+
+// Push the batch, retry with backoff until exit is closed, return the entries which are not pushed,
+// exit nil pushes once without retry. The push error is recorded by Loop SetError.
+type Push func(batch []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{}
+
+// Loop config.
+type Config struct {
+	// Writer name of errors, e.g. grpc.
+	Name string
+	// The max amount of buffered entries.
+	BufferSize int
+	// Max entries in one batch.
+	BatchSize int
+	// Max bytes of entries in one batch, zero is unlimited.
+	BatchBytes int
+	// Push interval of the pending entries.
+	FlushInterval time.Duration
+	// Time allowed to sync.
+	Timeout time.Duration
+	// Minimum retry backoff.
+	MinBackoff time.Duration
+	// Maximum retry backoff.
+	MaxBackoff time.Duration
+	// Push batch.
+	Push Push
+	// Get bytes of entry, required by BatchBytes.
+	Bytes func(e interface{}) int
+	// Block Add until the entry is buffered or the loop is closing, otherwise the entry is dropped
+	// when the buffer is full.
+	Block bool
+}
+
+// Batch loop buffers the entries of writer and pushes them in batches by a goroutine,
+// a batch is pushed when it is full, by flush interval and by Sync, the buffered entries
+// are pushed once more without retry by Close. The zero value is ready to start.
+type Loop struct {
+	config Config
+	// close lock, entries are added with read lock.
+	mutex   sync.RWMutex
+	closed  bool
+	entries chan interface{}
+	flush   chan chan error
+	exit    chan struct{}
+	done    chan struct{}
+	// closing unblocks the blocked Add before Close takes the close lock.
+	closing     chan struct{}
+	closingOnce sync.Once
+	closeOnce   sync.Once
+
+	errMutex sync.Mutex
+	// last push error.
+	err error
+	// errors of the dropped entries since the last sync.
+	errs error
+	// dropped entries count.
+	dropped uint64
+}
+
+// Start the loop, it should be called once before Add, the loop is not started after closed.
+func (l *Loop) Start(config Config) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed || l.entries != nil {
+		return
+	}
+
+	l.config = config
+	l.initClosing()
+	l.entries = make(chan interface{}, config.BufferSize)
+	l.flush = make(chan chan error)
+	l.exit = make(chan struct{})
+	l.done = make(chan struct{})
+
+	go l.run()
+}
+
+// Add entry to the buffer, the entry is dropped when the buffer is full.
+// The entry is added with lock, so it is not lost by a concurrent Close.
+func (l *Loop) Add(e interface{}) error {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if l.closed || l.entries == nil {
+		return fmt.Errorf("%s logger is closed", l.config.Name)
+	}
+
+	if l.config.Block {
+		select {
+		case l.entries <- e:
+			return nil
+		case <-l.closing:
+			return fmt.Errorf("%s logger is closed", l.config.Name)
+		}
+	}
+
+	select {
+	case l.entries <- e:
+	default:
+		atomic.AddUint64(&l.dropped, 1)
+		return errors.New("entry buffer is full")
+	}
+
+	return nil
+}
+
+// Sync push the buffered entries, return the error of the entries which are not pushed
+// and the errors of the entries dropped since the last sync.
+func (l *Loop) Sync() error {
+	l.mutex.RLock()
+	started, closed := l.entries != nil, l.closed
+	l.mutex.RUnlock()
+	if !started || closed {
+		return nil
+	}
+
+	timer := time.NewTimer(l.config.Timeout)
+	defer timer.Stop()
+
+	reply := make(chan error, 1)
+	select {
+	case l.flush <- reply:
+	case <-l.done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%s logger sync timeout", l.config.Name)
+	}
+
+	select {
+	case err := <-reply:
+		return err
+	case <-timer.C:
+		return fmt.Errorf("%s logger sync timeout", l.config.Name)
+	}
+}
+
+// Close the loop, push the buffered entries once more without retry, return the last push error.
+// The entries which are not pushed are dropped.
+func (l *Loop) Close(name string) error {
+	l.initClosing()
+	l.closeOnce.Do(func() {
+		close(l.closing)
+	})
+
+	l.mutex.Lock()
+	if l.closed {
+		l.mutex.Unlock()
+		return fmt.Errorf("%s logger is already closed", name)
+	}
+	l.closed = true
+	started := l.entries != nil
+	l.mutex.Unlock()
+
+	if !started {
+		return nil
+	}
+
+	close(l.exit)
+	<-l.done
+
+	return l.Err()
+}
+
+// init closing channel once.
+func (l *Loop) initClosing() {
+	l.closingOnce.Do(func() {
+		l.closing = make(chan struct{})
+	})
+}
+
+// Get health, the last push error or the closed error.
+func (l *Loop) Health(name string) error {
+	l.mutex.RLock()
+	closed := l.closed
+	l.mutex.RUnlock()
+
+	if closed {
+		return fmt.Errorf("%s logger is closed", name)
+	}
+
+	return l.Err()
+}
+
+// Get last push error.
+func (l *Loop) Err() error {
+	l.errMutex.Lock()
+	defer l.errMutex.Unlock()
+
+	return l.err
+}
+
+// Set last push error.
+func (l *Loop) SetError(err error) {
+	l.errMutex.Lock()
+	l.err = err
+	l.errMutex.Unlock()
+}
+
+// Drop entries, e.g. the entries are rejected or out of retries.
+func (l *Loop) Drop(n int) {
+	atomic.AddUint64(&l.dropped, uint64(n))
+}
+
+// Drop entries with error, the error is returned by the next Sync, the same error is returned once.
+func (l *Loop) DropError(n int, err error) {
+	l.Drop(n)
+
+	l.errMutex.Lock()
+	defer l.errMutex.Unlock()
+
+	for _, e := range multierr.Errors(l.errs) {
+		if e.Error() == err.Error() {
+			return
+		}
+	}
+	l.errs = multierr.Append(l.errs, err)
+}
+
+// take the errors of the dropped entries since the last sync.
+func (l *Loop) takeErrors() error {
+	l.errMutex.Lock()
+	defer l.errMutex.Unlock()
+
+	errs := l.errs
+	l.errs = nil
+	return errs
+}
+
+// Get dropped entries count.
+func (l *Loop) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// push loop, collect entries into batches and push them.
+func (l *Loop) run() {
+	defer close(l.done)
+
+	c := l.config
+
+	ticker := time.NewTicker(c.FlushInterval)
+	defer ticker.Stop()
+
+	b := &backoff.Backoff{Min: c.MinBackoff, Max: c.MaxBackoff, Jitter: 0.2}
+
+	batch := make([]interface{}, 0, c.BatchSize)
+	size := 0
+
+	// push batch, the size is recounted by the entries which are not pushed.
+	push := func(b *backoff.Backoff, exit <-chan struct{}) {
+		batch = c.Push(batch, b, exit)
+		size = 0
+		if c.BatchBytes > 0 {
+			for _, e := range batch {
+				size += c.Bytes(e)
+			}
+		}
+	}
+
+	// drain buffered entries into batch.
+	drain := func() {
+		for n := len(l.entries); n > 0; n-- {
+			batch = append(batch, <-l.entries)
+		}
+	}
+
+	for {
+		select {
+		case e := <-l.entries:
+			batch = append(batch, e)
+			if c.BatchBytes > 0 {
+				size += c.Bytes(e)
+			}
+			if len(batch) >= c.BatchSize || (c.BatchBytes > 0 && size >= c.BatchBytes) {
+				push(b, l.exit)
+			}
+		case <-ticker.C:
+			push(b, l.exit)
+		case reply := <-l.flush:
+			drain()
+			push(b, l.exit)
+			reply <- multierr.Append(l.pending(batch), l.takeErrors())
+		case <-l.exit:
+			drain()
+			// push the remaining entries once more without retry.
+			push(&backoff.Backoff{}, nil)
+			if err := l.pending(batch); err != nil {
+				l.Drop(len(batch))
+				l.SetError(err)
+			}
+			return
+		}
+	}
+}
+
+// get pending batch error.
+func (l *Loop) pending(batch []interface{}) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d entries are not sent: %v", len(batch), l.Err())
+}
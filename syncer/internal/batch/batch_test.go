@@ -0,0 +1,111 @@
+package batch
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-framework/zap/syncer/internal/backoff"
+)
+
+// new config pushing into pushed, the batch fails when fail is set.
+func newConfig(pushed *int64, fail *int32) Config {
+	return Config{
+		Name:          "test",
+		BufferSize:    100,
+		BatchSize:     10,
+		FlushInterval: time.Hour,
+		Timeout:       time.Second,
+		MinBackoff:    time.Millisecond,
+		MaxBackoff:    time.Millisecond,
+		Push: func(batch []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+			if atomic.LoadInt32(fail) != 0 {
+				return batch
+			}
+			atomic.AddInt64(pushed, int64(len(batch)))
+			return batch[:0]
+		},
+	}
+}
+
+func TestLoop_Sync(t *testing.T) {
+	var pushed int64
+	var fail int32
+
+	l := &Loop{}
+	if err := l.Add(1); err == nil {
+		t.Error("expect error of not started loop")
+	}
+
+	l.Start(newConfig(&pushed, &fail))
+	for i := 0; i < 5; i++ {
+		if err := l.Add(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if pushed != 5 {
+		t.Errorf("expect 5 pushed entries, got %d", pushed)
+	}
+
+	atomic.StoreInt32(&fail, 1)
+	l.SetError(errors.New("unavailable"))
+	l.Add(1)
+	if err := l.Sync(); err == nil || err.Error() != "1 entries are not sent: unavailable" {
+		t.Errorf("unexpected sync error %v", err)
+	}
+
+	if err := l.Close("test"); err == nil {
+		t.Error("expect error of the entry not sent")
+	}
+	if l.Dropped() != 1 {
+		t.Errorf("expect 1 dropped entry, got %d", l.Dropped())
+	}
+	if err := l.Close("test"); err == nil {
+		t.Error("expect error of closing twice")
+	}
+	if err := l.Add(1); err == nil {
+		t.Error("expect error of closed loop")
+	}
+}
+
+func TestLoop_AddClose(t *testing.T) {
+	for n := 0; n < 20; n++ {
+		var pushed int64
+		var fail int32
+
+		// the buffer is not full, so the dropped entries are the ones not pushed.
+		config := newConfig(&pushed, &fail)
+		config.BufferSize = 1000
+
+		l := &Loop{}
+		l.Start(config)
+
+		var added int64
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					if l.Add(j) == nil {
+						atomic.AddInt64(&added, 1)
+					}
+				}
+			}()
+		}
+
+		time.Sleep(time.Millisecond)
+		l.Close("test")
+		wg.Wait()
+
+		// every added entry is pushed or dropped, none is lost by the concurrent close.
+		if got := atomic.LoadInt64(&pushed) + int64(l.Dropped()); got != added {
+			t.Fatalf("expect %d pushed entries, got %d", added, got)
+		}
+	}
+}
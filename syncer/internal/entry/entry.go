@@ -0,0 +1,239 @@
+package entry
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap/zapcore"
+)
+
+// Color escape of colored level encoders.
+var colorEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// Json decoder keeps numbers as json.Number.
+var decoder = jsoniter.Config{UseNumber: true}.Froze()
+
+// Entry keys of zap json encoder, empty key is the default key.
+type Keys struct {
+	// Time key, default is ts.
+	Time string
+	// Level key, default is level.
+	Level string
+	// Logger name key, default is logger.
+	Name string
+	// Caller key, default is caller.
+	Caller string
+	// Message key, default is msg.
+	Message string
+	// Stacktrace key, default is stacktrace.
+	Stacktrace string
+}
+
+// Get keys of encoder config.
+func NewKeys(config zapcore.EncoderConfig) Keys {
+	return Keys{
+		Time:       config.TimeKey,
+		Level:      config.LevelKey,
+		Name:       config.NameKey,
+		Caller:     config.CallerKey,
+		Message:    config.MessageKey,
+		Stacktrace: config.StacktraceKey,
+	}
+}
+
+// Default keys of zap production encoder.
+var DefaultKeys = Keys{
+	Time:       "ts",
+	Level:      "level",
+	Name:       "logger",
+	Caller:     "caller",
+	Message:    "msg",
+	Stacktrace: "stacktrace",
+}
+
+// Get keys with default keys for the empty keys.
+func (k Keys) WithDefault() Keys {
+	if k.Time == "" {
+		k.Time = DefaultKeys.Time
+	}
+	if k.Level == "" {
+		k.Level = DefaultKeys.Level
+	}
+	if k.Name == "" {
+		k.Name = DefaultKeys.Name
+	}
+	if k.Caller == "" {
+		k.Caller = DefaultKeys.Caller
+	}
+	if k.Message == "" {
+		k.Message = DefaultKeys.Message
+	}
+	if k.Stacktrace == "" {
+		k.Stacktrace = DefaultKeys.Stacktrace
+	}
+	return k
+}
+
+// Entry parser with the keys of write encoder config, it is safe for concurrent use.
+type Parser struct {
+	keys atomic.Value
+}
+
+// Set keys by the write encoder config.
+func (p *Parser) SetEncoderConfig(config zapcore.EncoderConfig) {
+	p.keys.Store(NewKeys(config))
+}
+
+// Get keys with default keys for the empty keys.
+func (p *Parser) Keys() Keys {
+	keys, _ := p.keys.Load().(Keys)
+	return keys.WithDefault()
+}
+
+// Parse zap json encoded line by the keys.
+func (p *Parser) Parse(line []byte) *Entry {
+	return Parse(line, p.Keys())
+}
+
+// Log entry parsed from zap json encoded line.
+type Entry struct {
+	// Entry time.
+	Time time.Time
+	// Entry level.
+	Level zapcore.Level
+	// Logger name.
+	Logger string
+	// Entry caller.
+	Caller string
+	// Entry message.
+	Message string
+	// Entry stacktrace.
+	Stack string
+	// Entry fields, numbers are json.Number.
+	Fields map[string]interface{}
+}
+
+// Parse zap json encoded line, the line which is not json is parsed as an info message at now.
+func Parse(p []byte, keys Keys) *Entry {
+	keys = keys.WithDefault()
+
+	line := bytes.TrimSpace(p)
+
+	var fields map[string]interface{}
+	if len(line) == 0 || line[0] != '{' || decoder.Unmarshal(line, &fields) != nil {
+		return &Entry{Time: time.Now(), Level: zapcore.InfoLevel, Message: string(line)}
+	}
+
+	e := &Entry{Time: time.Now(), Level: zapcore.InfoLevel, Fields: fields}
+
+	if v, ok := fields[keys.Time]; ok {
+		if t, ok := parseTime(v); ok {
+			e.Time = t
+			delete(fields, keys.Time)
+		}
+	}
+	if v, ok := fields[keys.Level].(string); ok {
+		v = colorEscape.ReplaceAllString(v, "")
+		if err := e.Level.UnmarshalText([]byte(strings.ToLower(v))); err == nil {
+			delete(fields, keys.Level)
+		}
+	}
+	e.Logger = takeString(fields, keys.Name)
+	e.Caller = takeString(fields, keys.Caller)
+	e.Message = takeString(fields, keys.Message)
+	e.Stack = takeString(fields, keys.Stacktrace)
+
+	if len(fields) == 0 {
+		e.Fields = nil
+	}
+
+	return e
+}
+
+// take string value of key.
+func takeString(fields map[string]interface{}, key string) string {
+	v, ok := fields[key].(string)
+	if ok {
+		delete(fields, key)
+	}
+	return v
+}
+
+// Time layouts of zap time encoders.
+var layouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.000Z0700",
+	"2006-01-02 15:04:05.000Z0700",
+}
+
+// parse time of epoch number or formatted string.
+func parseTime(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case json.Number:
+		return epoch(string(v))
+	case string:
+		for _, layout := range layouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// convert epoch number to time, the unit is guessed by the magnitude as seconds, millis or nanos.
+func epoch(s string) (time.Time, bool) {
+	// exponent format.
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		s = strconv.FormatFloat(f, 'f', -1, 64)
+	}
+
+	integer, fraction := s, ""
+	if n := strings.IndexByte(s, '.'); n >= 0 {
+		integer, fraction = s[:n], s[n+1:]
+	}
+
+	i, err := strconv.ParseInt(integer, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	// unit of integer part.
+	var unit int64
+	switch a := strings.TrimPrefix(integer, "-"); {
+	case len(a) >= 18:
+		return time.Unix(0, i), true
+	case len(a) >= 12:
+		unit = int64(time.Millisecond)
+	default:
+		unit = int64(time.Second)
+	}
+
+	// fraction of unit in nanoseconds.
+	var frac int64
+	if fraction != "" {
+		digits := len(strconv.FormatInt(unit, 10)) - 1
+		if len(fraction) > digits {
+			fraction = fraction[:digits]
+		}
+		fraction += strings.Repeat("0", digits-len(fraction))
+		if frac, err = strconv.ParseInt(fraction, 10, 64); err != nil {
+			return time.Time{}, false
+		}
+		if i < 0 {
+			frac = -frac
+		}
+	}
+
+	return time.Unix(0, i*unit+frac), true
+}
@@ -0,0 +1,88 @@
+package entry
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParse(t *testing.T) {
+	ts := time.Date(2020, 1, 2, 3, 4, 5, 600000000, time.UTC)
+
+	tests := []struct {
+		line    string
+		keys    Keys
+		time    time.Time
+		level   zapcore.Level
+		logger  string
+		message string
+		fields  map[string]interface{}
+	}{
+		{
+			line:    `{"level":"warn","ts":1577934245.6,"logger":"db","caller":"a.go:1","msg":"hello","count":1}`,
+			time:    ts,
+			level:   zapcore.WarnLevel,
+			logger:  "db",
+			message: "hello",
+			fields:  map[string]interface{}{"count": json.Number("1")},
+		},
+		{
+			line:    `{"L":"ERROR","T":"2020-01-02T03:04:05.600Z","M":"hello"}` + "\n",
+			keys:    Keys{Level: "L", Time: "T", Message: "M"},
+			time:    ts,
+			level:   zapcore.ErrorLevel,
+			message: "hello",
+		},
+		{
+			line:    `{"L":"\u001b[31mERROR\u001b[0m","T":"2020-01-02T03:04:05.600Z","M":"colored"}`,
+			keys:    NewKeys(zapcore.EncoderConfig{LevelKey: "L", TimeKey: "T", MessageKey: "M"}),
+			time:    ts,
+			level:   zapcore.ErrorLevel,
+			message: "colored",
+		},
+		{
+			line:    `{"level":"info","ts":1577934245600,"msg":"millis"}`,
+			time:    ts,
+			level:   zapcore.InfoLevel,
+			message: "millis",
+		},
+		{
+			line:    `{"level":"debug","ts":1577934245600000000,"msg":"nanos"}`,
+			time:    ts,
+			level:   zapcore.DebugLevel,
+			message: "nanos",
+		},
+		{
+			line:    `{"level":"verbose","msg":"unknown level"}`,
+			level:   zapcore.InfoLevel,
+			message: "unknown level",
+			fields:  map[string]interface{}{"level": "verbose"},
+		},
+		{
+			line:    "plain text line\n",
+			level:   zapcore.InfoLevel,
+			message: "plain text line",
+		},
+	}
+
+	for _, test := range tests {
+		e := Parse([]byte(test.line), test.keys)
+
+		if !test.time.IsZero() && !e.Time.Equal(test.time) {
+			t.Errorf("%s expect time %s, got %s", test.line, test.time, e.Time)
+		}
+		if e.Level != test.level || e.Logger != test.logger || e.Message != test.message {
+			t.Errorf("%s unexpected entry %+v", test.line, e)
+		}
+		if len(e.Fields) != len(test.fields) {
+			t.Errorf("%s expect fields %v, got %v", test.line, test.fields, e.Fields)
+		}
+		for k, v := range test.fields {
+			if e.Fields[k] != v {
+				t.Errorf("%s expect field %s %v, got %v", test.line, k, v, e.Fields[k])
+			}
+		}
+	}
+}
@@ -0,0 +1,424 @@
+package msgpack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Event time of fluent forward protocol, encoded as extension type 0.
+type EventTime time.Time
+
+// append big endian uint32.
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+// append big endian uint64.
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v>>32)), uint32(v))
+}
+
+// Append nil.
+func AppendNil(b []byte) []byte {
+	return append(b, 0xc0)
+}
+
+// Append bool.
+func AppendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, 0xc3)
+	}
+	return append(b, 0xc2)
+}
+
+// Append int.
+func AppendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return AppendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, 0xd0, byte(v))
+	case v >= math.MinInt16:
+		return append(b, 0xd1, byte(v>>8), byte(v))
+	case v >= math.MinInt32:
+		return appendUint32(append(b, 0xd2), uint32(v))
+	}
+	return appendUint64(append(b, 0xd3), uint64(v))
+}
+
+// Append uint.
+func AppendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, 0xcc, byte(v))
+	case v <= math.MaxUint16:
+		return append(b, 0xcd, byte(v>>8), byte(v))
+	case v <= math.MaxUint32:
+		return appendUint32(append(b, 0xce), uint32(v))
+	}
+	return appendUint64(append(b, 0xcf), v)
+}
+
+// Append float64.
+func AppendFloat(b []byte, v float64) []byte {
+	return appendUint64(append(b, 0xcb), math.Float64bits(v))
+}
+
+// Append string.
+func AppendString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n <= 31:
+		b = append(b, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xda, byte(n>>8), byte(n))
+	default:
+		b = appendUint32(append(b, 0xdb), uint32(n))
+	}
+	return append(b, s...)
+}
+
+// Append binary.
+func AppendBytes(b []byte, p []byte) []byte {
+	n := len(p)
+	switch {
+	case n <= math.MaxUint8:
+		b = append(b, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, 0xc5, byte(n>>8), byte(n))
+	default:
+		b = appendUint32(append(b, 0xc6), uint32(n))
+	}
+	return append(b, p...)
+}
+
+// Append array header of n elements.
+func AppendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x90|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, 0xdc, byte(n>>8), byte(n))
+	}
+	return appendUint32(append(b, 0xdd), uint32(n))
+}
+
+// Append map header of n pairs.
+func AppendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n <= 15:
+		return append(b, 0x80|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, 0xde, byte(n>>8), byte(n))
+	}
+	return appendUint32(append(b, 0xdf), uint32(n))
+}
+
+// Append event time as fixext8 of type 0 with seconds and nanoseconds.
+func AppendEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = appendUint32(b, uint32(t.Unix()))
+	return appendUint32(b, uint32(t.Nanosecond()))
+}
+
+// Append value, maps are encoded with sorted keys, unsupported values are encoded as strings.
+func Append(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return AppendNil(b)
+	case bool:
+		return AppendBool(b, v)
+	case string:
+		return AppendString(b, v)
+	case []byte:
+		return AppendBytes(b, v)
+	case int:
+		return AppendInt(b, int64(v))
+	case int64:
+		return AppendInt(b, v)
+	case int32:
+		return AppendInt(b, int64(v))
+	case uint:
+		return AppendUint(b, uint64(v))
+	case uint64:
+		return AppendUint(b, v)
+	case uint32:
+		return AppendUint(b, uint64(v))
+	case float64:
+		return AppendFloat(b, v)
+	case float32:
+		return AppendFloat(b, float64(v))
+	case json.Number:
+		if i, err := strconv.ParseInt(string(v), 10, 64); err == nil {
+			return AppendInt(b, i)
+		}
+		if f, err := strconv.ParseFloat(string(v), 64); err == nil {
+			return AppendFloat(b, f)
+		}
+		return AppendString(b, string(v))
+	case EventTime:
+		return AppendEventTime(b, time.Time(v))
+	case time.Time:
+		return AppendString(b, v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return AppendString(b, v.String())
+	case []interface{}:
+		b = AppendArrayHeader(b, len(v))
+		for _, item := range v {
+			b = Append(b, item)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = AppendMapHeader(b, len(v))
+		for _, k := range keys {
+			b = AppendString(b, k)
+			b = Append(b, v[k])
+		}
+		return b
+	case map[string]string:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		b = AppendMapHeader(b, len(v))
+		for _, k := range keys {
+			b = AppendString(b, k)
+			b = AppendString(b, v[k])
+		}
+		return b
+	case error:
+		return AppendString(b, v.Error())
+	case fmt.Stringer:
+		return AppendString(b, v.String())
+	}
+
+	// slices and maps of other types.
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		b = AppendArrayHeader(b, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			b = Append(b, rv.Index(i).Interface())
+		}
+		return b
+	case reflect.Map:
+		values := make(map[string]interface{}, rv.Len())
+		for _, key := range rv.MapKeys() {
+			values[fmt.Sprint(key.Interface())] = rv.MapIndex(key).Interface()
+		}
+		return Append(b, values)
+	}
+
+	return AppendString(b, fmt.Sprint(v))
+}
+
+// Decode one value from r, maps are decoded as map[string]interface{}, integers as int64 or uint64,
+// binaries as []byte and extensions as []byte of their data.
+func Decode(r io.Reader) (interface{}, error) {
+	d := decoder{r: r}
+	return d.decode()
+}
+
+// msgpack decoder.
+type decoder struct {
+	r   io.Reader
+	buf [8]byte
+}
+
+// read n bytes.
+func (d *decoder) read(n int) ([]byte, error) {
+	if n <= len(d.buf) {
+		if _, err := io.ReadFull(d.r, d.buf[:n]); err != nil {
+			return nil, err
+		}
+		return d.buf[:n], nil
+	}
+
+	p := make([]byte, n)
+	if _, err := io.ReadFull(d.r, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// read big endian unsigned integer of n bytes.
+func (d *decoder) uint(n int) (uint64, error) {
+	p, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+
+	var v uint64
+	for _, c := range p {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// read n bytes as a copy.
+func (d *decoder) bytes(n uint64) ([]byte, error) {
+	if n > math.MaxInt32 {
+		return nil, errors.New("msgpack: length is too large")
+	}
+	p := make([]byte, n)
+	if _, err := io.ReadFull(d.r, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// capacity of n elements, the length is not trusted.
+func capacity(n uint64) int {
+	if n > 1024 {
+		return 1024
+	}
+	return int(n)
+}
+
+// decode array of n elements.
+func (d *decoder) array(n uint64) (interface{}, error) {
+	values := make([]interface{}, 0, capacity(n))
+	for i := uint64(0); i < n; i++ {
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// decode map of n pairs.
+func (d *decoder) dict(n uint64) (interface{}, error) {
+	values := make(map[string]interface{}, capacity(n))
+	for i := uint64(0); i < n; i++ {
+		k, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		v, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		switch k := k.(type) {
+		case string:
+			values[k] = v
+		case []byte:
+			values[string(k)] = v
+		default:
+			values[fmt.Sprint(k)] = v
+		}
+	}
+	return values, nil
+}
+
+// decode one value.
+func (d *decoder) decode() (interface{}, error) {
+	p, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := p[0]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.dict(uint64(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.array(uint64(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		s, err := d.bytes(uint64(c & 0x1f))
+		return string(s), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return d.uint(1 << (c - 0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		v, err := d.uint(size)
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - size*8)
+		return int64(v<<shift) >> shift, nil
+	case 0xca:
+		v, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case 0xcb:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.uint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.bytes(n)
+		return string(s), err
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.uint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		return d.bytes(n)
+	case 0xdc, 0xdd:
+		n, err := d.uint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.array(n)
+	case 0xde, 0xdf:
+		n, err := d.uint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.dict(n)
+	case 0xd4, 0xd5, 0xd6, 0xd7, 0xd8:
+		// fixext with type.
+		if _, err := d.read(1); err != nil {
+			return nil, err
+		}
+		return d.bytes(1 << (c - 0xd4))
+	case 0xc7, 0xc8, 0xc9:
+		n, err := d.uint(1 << (c - 0xc7))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := d.read(1); err != nil {
+			return nil, err
+		}
+		return d.bytes(n)
+	}
+
+	return nil, fmt.Errorf("msgpack: unknown format 0x%02x", c)
+}
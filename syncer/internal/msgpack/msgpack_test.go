@@ -0,0 +1,64 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAppend(t *testing.T) {
+	tests := []struct {
+		value  interface{}
+		expect interface{}
+	}{
+		{nil, nil},
+		{true, true},
+		{int64(1), int64(1)},
+		{int64(-1), int64(-1)},
+		{int64(-200), int64(-200)},
+		{int64(math.MinInt64), int64(math.MinInt64)},
+		{uint64(200), uint64(200)},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{1.5, 1.5},
+		{json.Number("42"), int64(42)},
+		{json.Number("4.2"), 4.2},
+		{"text", "text"},
+		{strings.Repeat("s", 300), strings.Repeat("s", 300)},
+		{[]byte("bin"), []byte("bin")},
+		{[]interface{}{"a", int64(1)}, []interface{}{"a", int64(1)}},
+		{[]string{"a", "b"}, []interface{}{"a", "b"}},
+		{map[string]interface{}{"k": map[string]interface{}{"n": nil}}, map[string]interface{}{"k": map[string]interface{}{"n": nil}}},
+		{map[string]string{"k": "v"}, map[string]interface{}{"k": "v"}},
+		{time.Second, "1s"},
+	}
+
+	for _, test := range tests {
+		v, err := Decode(bytes.NewReader(Append(nil, test.value)))
+		if err != nil {
+			t.Errorf("decode %v: %v", test.value, err)
+			continue
+		}
+		// small unsigned integers are decoded as int64.
+		if u, ok := v.(uint64); ok && u <= math.MaxInt64 {
+			if _, ok := test.expect.(uint64); !ok {
+				v = int64(u)
+			}
+		}
+		if !reflect.DeepEqual(v, test.expect) {
+			t.Errorf("value: %#v, expect: %#v", v, test.expect)
+		}
+	}
+}
+
+func TestAppendEventTime(t *testing.T) {
+	b := AppendEventTime(nil, time.Unix(1600000000, 500))
+
+	expect := []byte{0xd7, 0x00, 0x5f, 0x5e, 0x10, 0x00, 0x00, 0x00, 0x01, 0xf4}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("event time: % x", b)
+	}
+}
@@ -0,0 +1,73 @@
+package redact
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Masked value of the redacted secrets.
+const Redacted = "******"
+
+// Mask string, empty string is kept.
+func String(s string) string {
+	if s == "" {
+		return s
+	}
+	return Redacted
+}
+
+// Mask the values of map, the keys are kept.
+func Map(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	n := make(map[string]string, len(m))
+	for k := range m {
+		n[k] = Redacted
+	}
+	return n
+}
+
+// Mask the url credentials, the user info password, or the user name without password,
+// and the query values, invalid url is masked entirely.
+func URL(s string) string {
+	if s == "" {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return Redacted
+	}
+
+	// the user info is formatted without escaping the mask.
+	user := ""
+	if u.User != nil {
+		if _, ok := u.User.Password(); ok {
+			user = url.User(u.User.Username()).String() + ":" + Redacted + "@"
+		} else {
+			user = Redacted + "@"
+		}
+		u.User = nil
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		keys := make([]string, 0, len(query))
+		for k := range query {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for i, k := range keys {
+			keys[i] = url.QueryEscape(k) + "=" + Redacted
+		}
+		u.RawQuery = strings.Join(keys, "&")
+	}
+
+	if user != "" {
+		return strings.Replace(u.String(), "//", "//"+user, 1)
+	}
+
+	return u.String()
+}
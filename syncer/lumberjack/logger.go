@@ -1,11 +1,14 @@
 package lumberjack
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/natefinch/lumberjack"
+	"go.uber.org/multierr"
 )
 
 const (
@@ -17,39 +20,82 @@ type Logger struct {
 	lumberjack.Logger `json:",inline" yaml:",inline" mapstructure:",squash"`
 }
 
-// Implement Cloner interface.
+// Implement Cloner interface, only the config is copied, the clone opens its own file.
 func (l *Logger) Clone() io.Writer {
-	n := *l
+	return &Logger{
+		Logger: lumberjack.Logger{
+			Filename:   l.Filename,
+			MaxSize:    l.MaxSize,
+			MaxAge:     l.MaxAge,
+			MaxBackups: l.MaxBackups,
+			LocalTime:  l.LocalTime,
+			Compress:   l.Compress,
+		},
+	}
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	if l.Filename != "" {
+		if info, err := os.Stat(filepath.Dir(l.Filename)); err == nil && !info.IsDir() {
+			errs = append(errs, fmt.Errorf("filename: %s is not in a directory", l.Filename))
+		}
+	}
+	if l.MaxSize < 0 {
+		errs = append(errs, errors.New("maxsize: must not be negative"))
+	}
+	if l.MaxAge < 0 {
+		errs = append(errs, errors.New("maxage: must not be negative"))
+	}
+	if l.MaxBackups < 0 {
+		errs = append(errs, errors.New("maxbackups: must not be negative"))
+	}
 
-	return &n
+	return multierr.Combine(errs...)
 }
 
-// Get default logger.
-func GetDefault() *Logger {
-	l := lumberjack.Logger{
-		Filename:   fmt.Sprintf("%s.log", os.Args[0]),
-		MaxSize:    500,
-		MaxBackups: 3,
-		MaxAge:     30,
-		Compress:   true,
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	filename := l.Filename
+	if filename == "" {
+		filename = filepath.Join(os.TempDir(), filepath.Base(os.Args[0])+"-lumberjack.log")
+	}
+
+	info, err := os.Stat(filepath.Dir(filename))
+	if err != nil {
+		return err
 	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not in a directory", filename)
+	}
+
+	return nil
+}
 
+// Get default logger.
+func GetDefault() *Logger {
 	return &Logger{
-		Logger: l,
+		Logger: lumberjack.Logger{
+			Filename:   fmt.Sprintf("%s.log", os.Args[0]),
+			MaxSize:    500,
+			MaxBackups: 3,
+			MaxAge:     30,
+			Compress:   true,
+		},
 	}
 }
 
 // New logger with filename.
 func New(filename string) *Logger {
-	l := lumberjack.Logger{
-		Filename:   filename,
-		MaxSize:    500,
-		MaxBackups: 3,
-		MaxAge:     30,
-		Compress:   true,
-	}
-
 	return &Logger{
-		Logger: l,
+		Logger: lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    500,
+			MaxBackups: 3,
+			MaxAge:     30,
+			Compress:   true,
+		},
 	}
 }
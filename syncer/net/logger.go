@@ -0,0 +1,351 @@
+package net
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/batch"
+)
+
+const (
+	// Name.
+	Name = "net"
+
+	// Default network.
+	Network = "tcp"
+	// Time allowed to dial.
+	DialTimeout = 5 * time.Second
+	// Time allowed to write a message.
+	WriteTimeout = 5 * time.Second
+	// Retries of a message before it is dropped.
+	MaxRetries = 5
+	// The max amount of buffered messages.
+	BufferSize = 10000
+	// Time allowed to sync the buffered messages.
+	SyncTimeout = 10 * time.Second
+)
+
+// Stream framings.
+const (
+	// Message is terminated by a line feed.
+	Newline = "newline"
+	// Message is prefixed with its length as 4 bytes big endian.
+	LengthPrefixed = "length-prefixed"
+)
+
+// Policies when the buffer is full.
+const (
+	// Drop the message.
+	Drop = "drop"
+	// Block the write until the message is buffered.
+	Block = "block"
+)
+
+// Net logger sends every entry as a message over tcp, udp or unix socket, e.g. to a local agent.
+// Messages are buffered and sent in order, a message is retried with backoff and redial
+// until max retries then dropped, datagram messages are sent without framing.
+type Logger struct {
+	// Network: tcp, udp, unix or unixgram.
+	Network string `json:"network" yaml:"network" mapstructure:"network"`
+	// Address as host:port, or socket path for unix networks.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Stream framing: newline or length-prefixed.
+	Framing string `json:"framing" yaml:"framing" mapstructure:"framing"`
+	// Time allowed to dial.
+	DialTimeout time.Duration `json:"dial_timeout" yaml:"dial_timeout" mapstructure:"dial_timeout"`
+	// Time allowed to write a message.
+	WriteTimeout time.Duration `json:"write_timeout" yaml:"write_timeout" mapstructure:"write_timeout"`
+	// Minimum retry backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum retry backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+	// Retries of a message before it is dropped.
+	MaxRetries int `json:"max_retries" yaml:"max_retries" mapstructure:"max_retries"`
+	// The max amount of buffered messages.
+	BufferSize int `json:"buffer_size" yaml:"buffer_size" mapstructure:"buffer_size"`
+	// Policy when the buffer is full: drop or block.
+	Policy string `json:"policy" yaml:"policy" mapstructure:"policy"`
+
+	once  sync.Once
+	loop  batch.Loop
+	mutex sync.RWMutex
+	conn  net.Conn
+}
+
+// New logger with network and address.
+func New(network, address string) *Logger {
+	l := GetDefault()
+	l.Network = network
+	l.Address = address
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		Network:      Network,
+		Framing:      Newline,
+		DialTimeout:  DialTimeout,
+		WriteTimeout: WriteTimeout,
+		MinBackoff:   backoff.DefaultMin,
+		MaxBackoff:   backoff.DefaultMax,
+		MaxRetries:   MaxRetries,
+		BufferSize:   BufferSize,
+		Policy:       Drop,
+	}
+}
+
+// Implement Cloner interface, only the config is copied, the clone sends by its own connection.
+func (l *Logger) Clone() io.Writer {
+	return &Logger{
+		Network:      l.Network,
+		Address:      l.Address,
+		Framing:      l.Framing,
+		DialTimeout:  l.DialTimeout,
+		WriteTimeout: l.WriteTimeout,
+		MinBackoff:   l.MinBackoff,
+		MaxBackoff:   l.MaxBackoff,
+		MaxRetries:   l.MaxRetries,
+		BufferSize:   l.BufferSize,
+		Policy:       l.Policy,
+	}
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	switch l.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		errs = append(errs, fmt.Errorf("network: not support %q", l.Network))
+	}
+	if l.Address == "" {
+		errs = append(errs, errors.New("address: must not be empty"))
+	}
+	if l.Framing != Newline && l.Framing != LengthPrefixed {
+		errs = append(errs, fmt.Errorf("framing: should be %s or %s", Newline, LengthPrefixed))
+	}
+	if l.DialTimeout <= 0 {
+		errs = append(errs, errors.New("dial_timeout: must be positive"))
+	}
+	if l.WriteTimeout <= 0 {
+		errs = append(errs, errors.New("write_timeout: must be positive"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+	if l.MaxRetries < 0 {
+		errs = append(errs, errors.New("max_retries: must not be negative"))
+	}
+	if l.BufferSize <= 0 {
+		errs = append(errs, errors.New("buffer_size: must be positive"))
+	}
+	if l.Policy != Drop && l.Policy != Block {
+		errs = append(errs, fmt.Errorf("policy: should be %s or %s", Drop, Block))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	return l.loop.Health(Name)
+}
+
+// Get dropped messages count.
+func (l *Logger) Dropped() uint64 {
+	return l.loop.Dropped()
+}
+
+// Implement Writer interface.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	l.once.Do(l.start)
+
+	// p is reused by the caller.
+	message := make([]byte, len(p))
+	copy(message, p)
+
+	if err := l.loop.Add(message); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Implement WriteSyncer interface, send the buffered messages,
+// return the errors of the messages dropped since the last sync.
+func (l *Logger) Sync() error {
+	return l.loop.Sync()
+}
+
+// Close, send the buffered messages without retry and close the connection.
+func (l *Logger) Close() error {
+	l.once.Do(func() {})
+
+	err := l.loop.Close(Name)
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.conn != nil {
+		err = multierr.Append(err, l.conn.Close())
+		l.conn = nil
+	}
+
+	return err
+}
+
+// start send loop, messages are sent one by one in order.
+func (l *Logger) start() {
+	size := l.BufferSize
+	if size <= 0 {
+		size = BufferSize
+	}
+
+	l.loop.Start(batch.Config{
+		Name:          Name,
+		BufferSize:    size,
+		BatchSize:     1,
+		FlushInterval: SyncTimeout,
+		Timeout:       SyncTimeout,
+		MinBackoff:    l.MinBackoff,
+		MaxBackoff:    l.MaxBackoff,
+		Push:          l.push,
+		Block:         l.Policy == Block,
+	})
+}
+
+// push messages in order, return the messages which are not sent when exit is nil,
+// the sending stops at the first failure without retry.
+func (l *Logger) push(messages []interface{}, b *backoff.Backoff, exit <-chan struct{}) []interface{} {
+	for i, message := range messages {
+		if err := l.send(message.([]byte), b, exit); err != nil && exit == nil {
+			return messages[i+1:]
+		}
+	}
+
+	return messages[:0]
+}
+
+// send message, retry with backoff until max retries or exit closed, the message is dropped on failure.
+// exit nil sends once without retry.
+func (l *Logger) send(message []byte, b *backoff.Backoff, exit <-chan struct{}) error {
+	message = l.frame(message)
+
+	for retries := 0; ; retries++ {
+		err := l.write(message)
+		l.loop.SetError(err)
+		if err == nil {
+			b.Reset()
+			return nil
+		}
+
+		if exit == nil || retries >= l.MaxRetries || !b.Sleep(exit) {
+			l.loop.DropError(1, err)
+			return err
+		}
+	}
+}
+
+// is network a datagram.
+func (l *Logger) datagram() bool {
+	switch l.Network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return true
+	}
+	return false
+}
+
+// frame message, datagram message is not framed.
+func (l *Logger) frame(message []byte) []byte {
+	if l.datagram() {
+		return message
+	}
+
+	if l.Framing == LengthPrefixed {
+		framed := make([]byte, 4+len(message))
+		binary.BigEndian.PutUint32(framed, uint32(len(message)))
+		copy(framed[4:], message)
+		return framed
+	}
+
+	if len(message) == 0 || message[len(message)-1] != '\n' {
+		message = append(message, '\n')
+	}
+
+	return message
+}
+
+// write message by the connection, the connection is closed on error and redialed by the next write.
+func (l *Logger) write(message []byte) error {
+	l.mutex.RLock()
+	conn := l.conn
+	l.mutex.RUnlock()
+
+	if conn == nil {
+		timeout := l.DialTimeout
+		if timeout <= 0 {
+			timeout = DialTimeout
+		}
+
+		var err error
+		if conn, err = net.DialTimeout(l.Network, l.Address, timeout); err != nil {
+			return err
+		}
+
+		l.mutex.Lock()
+		l.conn = conn
+		l.mutex.Unlock()
+
+		if !l.datagram() {
+			go l.watch(conn)
+		}
+	}
+
+	timeout := l.WriteTimeout
+	if timeout <= 0 {
+		timeout = WriteTimeout
+	}
+
+	err := conn.SetWriteDeadline(time.Now().Add(timeout))
+	if err == nil {
+		_, err = conn.Write(message)
+	}
+	if err != nil {
+		conn.Close()
+		l.mutex.Lock()
+		if l.conn == conn {
+			l.conn = nil
+		}
+		l.mutex.Unlock()
+	}
+
+	return err
+}
+
+// watch stream connection, the connection closed by the peer is closed and redialed by the next write.
+func (l *Logger) watch(conn net.Conn) {
+	buf := make([]byte, 512)
+	for {
+		if _, err := conn.Read(buf); err != nil {
+			break
+		}
+	}
+
+	conn.Close()
+
+	l.mutex.Lock()
+	if l.conn == conn {
+		l.conn = nil
+	}
+	l.mutex.Unlock()
+}
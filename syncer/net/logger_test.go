@@ -0,0 +1,288 @@
+package net
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// accept connections of listener.
+func accept(listener net.Listener) chan net.Conn {
+	conns := make(chan net.Conn, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+	return conns
+}
+
+// receive connection or fail after timeout.
+func receive(t *testing.T, conns chan net.Conn) net.Conn {
+	t.Helper()
+
+	select {
+	case conn := <-conns:
+		return conn
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection")
+	}
+	return nil
+}
+
+func TestLogger_Newline(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	conns := accept(listener)
+
+	l := New("tcp", listener.Addr().String())
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = 10 * time.Millisecond
+	defer l.Close()
+
+	lines := []string{`{"msg":"first"}` + "\n", `{"msg":"second"}`}
+	for _, line := range lines {
+		if _, err := l.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := receive(t, conns)
+	r := bufio.NewReader(conn)
+	for _, line := range lines {
+		s, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s != strings.TrimSuffix(line, "\n")+"\n" {
+			t.Errorf("line: %q", s)
+		}
+	}
+
+	// the peer closes the connection, the next message is sent by a new connection.
+	conn.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	if _, err := l.Write([]byte(`{"msg":"third"}` + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn = receive(t, conns)
+	defer conn.Close()
+	if s, err := bufio.NewReader(conn).ReadString('\n'); err != nil || s != `{"msg":"third"}`+"\n" {
+		t.Errorf("line: %q %v", s, err)
+	}
+}
+
+func TestLogger_LengthPrefixed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	conns := accept(listener)
+
+	l := New("tcp", listener.Addr().String())
+	l.Framing = LengthPrefixed
+	defer l.Close()
+
+	line := `{"msg":"framed"}` + "\n"
+	if _, err := l.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+
+	conn := receive(t, conns)
+	defer conn.Close()
+
+	var size uint32
+	if err := binary.Read(conn, binary.BigEndian, &size); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(conn, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != line {
+		t.Errorf("message: %q", buf)
+	}
+}
+
+func TestLogger_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l := New("udp", conn.LocalAddr().String())
+	defer l.Close()
+
+	line := `{"msg":"datagram"}` + "\n"
+	if _, err := l.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:n]) != line {
+		t.Errorf("datagram: %q", buf[:n])
+	}
+}
+
+func TestLogger_Retries(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	l := New("tcp", address)
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = time.Millisecond
+	l.MaxRetries = 2
+	defer l.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := l.Write([]byte("message\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Sync(); err == nil {
+		t.Error("sync should fail")
+	}
+
+	if n := l.Dropped(); n != 3 {
+		t.Errorf("dropped: %d", n)
+	}
+	if l.Health() == nil {
+		t.Error("logger should be unhealthy")
+	}
+}
+
+func TestLogger_CloseUnavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	// the first message is retrying, the others are buffered.
+	l := New("tcp", address)
+	l.MinBackoff = time.Hour
+	l.MaxBackoff = time.Hour
+
+	l.Write([]byte("first\n"))
+	time.Sleep(50 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if _, err := l.Write([]byte("buffered\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the buffered messages are dropped after the first failure.
+	l.Close()
+	if n := l.Dropped(); n != 4 {
+		t.Errorf("dropped: %d", n)
+	}
+}
+
+func TestLogger_Policy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	// the first message is retrying, the second is buffered.
+	l := New("tcp", address)
+	l.MinBackoff = time.Hour
+	l.MaxBackoff = time.Hour
+	l.BufferSize = 1
+
+	l.Write([]byte("first\n"))
+	time.Sleep(50 * time.Millisecond)
+	l.Write([]byte("second\n"))
+	if _, err := l.Write([]byte("third\n")); err == nil {
+		t.Error("write should fail when the buffer is full")
+	}
+	if l.Dropped() == 0 {
+		t.Error("message should be dropped")
+	}
+	l.Close()
+
+	// block until closed.
+	l = New("tcp", address)
+	l.MinBackoff = time.Hour
+	l.MaxBackoff = time.Hour
+	l.BufferSize = 1
+	l.Policy = Block
+
+	l.Write([]byte("first\n"))
+	time.Sleep(50 * time.Millisecond)
+	l.Write([]byte("second\n"))
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := l.Write([]byte("third\n"))
+		result <- err
+	}()
+
+	select {
+	case err := <-result:
+		t.Fatalf("write should block: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	l.Close()
+
+	select {
+	case err := <-result:
+		if err == nil {
+			t.Error("write should fail when closed")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("write should be unblocked when closed")
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	l := New("tcp", "localhost:9000")
+	if err := l.Validate(); err != nil {
+		t.Errorf("logger should be valid: %v", err)
+	}
+
+	l = &Logger{Network: "http", MaxRetries: -1, MinBackoff: time.Second}
+	err := l.Validate()
+	if err == nil {
+		t.Fatal("logger should be invalid")
+	}
+	for _, expect := range []string{"network", "address", "framing", "dial_timeout", "write_timeout", "max_backoff", "max_retries", "buffer_size", "policy"} {
+		if !strings.Contains(err.Error(), expect+":") {
+			t.Errorf("error should contain %s: %v", expect, err)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package syncer
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Writer factory returns a new writer with default config,
+// the writer is decoded from the write config and started by the logger when it implements Starter.
+type Factory func() io.Writer
+
+// Registered writer.
+type registration struct {
+	factory     Factory
+	description string
+}
+
+// Global registered writers.
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[string]*registration)
+)
+
+// Register writer factory with description, return error when the name is empty or registered.
+// Writer should tag as `json:",inline" yaml:",inline" mapstructure:",squash"` format.
+func RegisterFactory(name string, factory Factory, description string) error {
+	if name == "" {
+		return errors.New("writer name must not be empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("writer %s factory must not be nil", name)
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("writer %s is already registered", name)
+	}
+
+	registry[name] = &registration{factory: factory, description: description}
+
+	return nil
+}
+
+// Register writer factory with description, panic when register failed.
+func MustRegisterFactory(name string, factory Factory, description string) {
+	if err := RegisterFactory(name, factory, description); err != nil {
+		panic(err)
+	}
+}
+
+// Register Writer prototype, it is kept for compatibility and replaces the registered writer,
+// new writers are cloned from the prototype when it implements Cloner, otherwise the prototype is shared.
+// Writer should tag as `json:",inline" yaml:",inline" mapstructure:",squash"` format.
+func RegisterWriter(name string, writer io.Writer) {
+	factory := func() io.Writer {
+		if cloner, ok := writer.(Cloner); ok {
+			return cloner.Clone()
+		}
+		return writer
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[name] = &registration{factory: factory}
+}
+
+// Unregister writer, return false when the name is not registered.
+func Unregister(name string) bool {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	if _, ok := registry[name]; !ok {
+		return false
+	}
+	delete(registry, name)
+
+	return true
+}
+
+// Get registered writer names sorted.
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get registered writer description.
+func Description(name string) (string, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	r, ok := registry[name]
+	if !ok {
+		return "", false
+	}
+
+	return r.description, true
+}
+
+// New registered writer with default config.
+func NewWriter(name string) (io.Writer, error) {
+	registryMutex.RLock()
+	r, ok := registry[name]
+	registryMutex.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("not support write: %s, supported writes: %s", name, strings.Join(Names(), ", "))
+	}
+
+	return r.factory(), nil
+}
@@ -0,0 +1,144 @@
+package syncer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Test writer records the config when started.
+type testWriter struct {
+	bytes.Buffer `mapstructure:"-"`
+	Prefix       string `mapstructure:"prefix"`
+	started      string
+}
+
+// Implement Starter interface.
+func (w *testWriter) Start() error {
+	w.started = w.Prefix
+	return nil
+}
+
+// Test writer implements Cloner.
+type testClonerWriter struct {
+	testWriter
+}
+
+// Implement Cloner interface.
+func (w *testClonerWriter) Clone() io.Writer {
+	return &testClonerWriter{testWriter{Prefix: w.Prefix}}
+}
+
+func TestRegisterFactory(t *testing.T) {
+	factory := func() io.Writer { return &testWriter{Prefix: "default"} }
+
+	if err := RegisterFactory("test", factory, "test writer"); err != nil {
+		t.Fatal(err)
+	}
+	defer Unregister("test")
+
+	if err := RegisterFactory("test", factory, "test writer"); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("expect duplicate error, got %v", err)
+	}
+	if err := RegisterFactory("", factory, ""); err == nil {
+		t.Error("expect empty name error")
+	}
+	if err := RegisterFactory("nil", nil, ""); err == nil {
+		t.Error("expect nil factory error")
+	}
+
+	if description, ok := Description("test"); !ok || description != "test writer" {
+		t.Errorf("unexpected description %q %t", description, ok)
+	}
+
+	names := Names()
+	t.Log("names", names)
+	if !sort.StringsAreSorted(names) || !strings.Contains(","+strings.Join(names, ",")+",", ",test,") {
+		t.Errorf("unexpected names %v", names)
+	}
+
+	// decoded, not started before validated.
+	write := &Write{}
+	if err := yaml.Unmarshal([]byte("name: test\nconfig:\n  prefix: decoded\n"), write); err != nil {
+		t.Fatal(err)
+	}
+	if w := write.GetWriter().(*testWriter); w.Prefix != "decoded" || w.started != "" {
+		t.Errorf("expect decoded config and not started, got %+v", w)
+	}
+
+	if err := write.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if w := write.GetWriter().(*testWriter); w.started != "decoded" {
+		t.Errorf("expect started with decoded config, got %+v", w)
+	}
+
+	// clone is not started.
+	cloner := &Write{Name: "test", Config: &testClonerWriter{testWriter{Prefix: "decoded", started: "decoded"}}}
+	if w := cloner.Clone().GetWriter().(*testClonerWriter); w.Prefix != "decoded" || w.started != "" {
+		t.Errorf("expect clone not started, got %+v", w)
+	}
+
+	// every write has own writer.
+	other := &Write{}
+	if err := yaml.Unmarshal([]byte("name: test\n"), other); err != nil {
+		t.Fatal(err)
+	}
+	if other.GetWriter() == write.GetWriter() || other.GetWriter().(*testWriter).Prefix != "default" {
+		t.Errorf("expect new writer, got %+v", other.GetWriter())
+	}
+
+	if !Unregister("test") || Unregister("test") {
+		t.Error("expect unregister once")
+	}
+	if err := yaml.Unmarshal([]byte("name: test\n"), &Write{}); err == nil || !strings.Contains(err.Error(), "supported writes") {
+		t.Errorf("expect not support error, got %v", err)
+	}
+}
+
+func TestRegisterWriter(t *testing.T) {
+	prototype := &testWriter{Prefix: "prototype"}
+
+	RegisterWriter("test", prototype)
+	defer Unregister("test")
+
+	// replaced without error.
+	RegisterWriter("test", prototype)
+
+	writer, err := NewWriter("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if writer != prototype {
+		t.Error("expect shared prototype when it does not implement Cloner")
+	}
+}
+
+func TestRegistry_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name := fmt.Sprintf("concurrent-%d", i)
+			if err := RegisterFactory(name, func() io.Writer { return &bytes.Buffer{} }, ""); err != nil {
+				t.Error(err)
+			}
+			_ = Names()
+			if _, err := NewWriter(name); err != nil {
+				t.Error(err)
+			}
+			Unregister(name)
+		}(i)
+	}
+
+	wg.Wait()
+}
@@ -7,21 +7,25 @@ import (
 
 	"github.com/json-iterator/go"
 	"github.com/mitchellh/mapstructure"
-)
-
-// Global enabled Writers.
-var gWriters = make(map[string]io.Writer)
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 
-// Register Writer.
-// Writer should tag as `json:",inline" yaml:",inline" mapstructure:",squash"` format.
-func RegisterWriter(name string, writer io.Writer) {
-	gWriters[name] = writer
-}
+	"github.com/go-framework/zap/encoder"
+)
 
 // Defined writers for Get Writer from config.
 type Write struct {
 	// Write name.
 	Name string `json:"name" yaml:"name"`
+	// Write minimum enabled level, default is the logger level.
+	Level *zapcore.Level `json:"level,omitempty" yaml:"level"`
+	// Write maximum enabled level, default is unlimited.
+	MaxLevel *zapcore.Level `json:"max_level,omitempty" yaml:"max_level"`
+	// Write encoding: json or console, default is depend on logger development mode.
+	Encoding string `json:"encoding,omitempty" yaml:"encoding"`
+	// Write encoder config overrides.
+	Encoder *encoder.Config `json:"encoder,omitempty" yaml:"encoder"`
 	// Write config which implement Writer.
 	Config io.Writer `json:"config" yaml:"config"`
 }
@@ -31,9 +35,163 @@ func (this *Write) GetWriter() io.Writer {
 	return this.Config
 }
 
+// Clone write, the writer is cloned when it implements Cloner, otherwise it is shared,
+// the cloned writer is not started.
+func (this *Write) Clone() *Write {
+	if this == nil {
+		return nil
+	}
+
+	write := *this
+
+	if this.Level != nil {
+		level := *this.Level
+		write.Level = &level
+	}
+	if this.MaxLevel != nil {
+		level := *this.MaxLevel
+		write.MaxLevel = &level
+	}
+	if this.Encoder != nil {
+		e := *this.Encoder
+		write.Encoder = &e
+	}
+	if cloner, ok := this.Config.(Cloner); ok {
+		write.Config = cloner.Clone()
+	}
+
+	return &write
+}
+
+// Get write encoding, the encoding of entry parser writer or the default encoding is used when not set.
+func (this *Write) GetEncoding(encoding string) string {
+	if this.Encoding != "" {
+		return this.Encoding
+	}
+	if parser, ok := this.Config.(EntryParser); ok && parser.Encoding() != "" {
+		return parser.Encoding()
+	}
+	return encoding
+}
+
+// Set minimum enabled level.
+func (this *Write) SetLevel(level zapcore.Level) *Write {
+	this.Level = &level
+	return this
+}
+
+// Set maximum enabled level.
+func (this *Write) SetMaxLevel(level zapcore.Level) *Write {
+	this.MaxLevel = &level
+	return this
+}
+
+// Set encoding.
+func (this *Write) SetEncoding(encoding string) *Write {
+	this.Encoding = encoding
+	return this
+}
+
+// Set encoder config overrides.
+func (this *Write) SetEncoder(config *encoder.Config) *Write {
+	this.Encoder = config
+	return this
+}
+
+// Validate write, errors are prefixed by field name.
+func (this *Write) Validate() error {
+	var errs []error
+
+	if this.Name == "" {
+		errs = append(errs, errors.New("name: must not be empty"))
+	}
+
+	if this.Config == nil {
+		errs = append(errs, errors.New("config: must not be empty"))
+	} else if validator, ok := this.Config.(Validator); ok {
+		for _, err := range multierr.Errors(validator.Validate()) {
+			errs = append(errs, fmt.Errorf("config.%v", err))
+		}
+	}
+
+	if this.Level != nil && this.MaxLevel != nil && *this.MaxLevel < *this.Level {
+		errs = append(errs, fmt.Errorf("max_level: %s must not be less than level %s", this.MaxLevel, this.Level))
+	}
+
+	if this.Encoding != "" {
+		if err := encoder.CheckEncoding(this.Encoding); err != nil {
+			errs = append(errs, fmt.Errorf("encoding: %v", err))
+		} else if parser, ok := this.Config.(EntryParser); ok && parser.Encoding() != "" && parser.Encoding() != this.Encoding {
+			errs = append(errs, fmt.Errorf("encoding: %s writer only supports %s", this.Name, parser.Encoding()))
+		}
+	}
+
+	if err := this.Encoder.Check(); err != nil {
+		errs = append(errs, fmt.Errorf("encoder: %v", err))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Start the writer when it implements Starter, it is called when the logger core is built
+// after the config is validated.
+func (this *Write) Start() error {
+	if starter, ok := this.Config.(Starter); ok {
+		if err := starter.Start(); err != nil {
+			return fmt.Errorf("config: start %s: %v", this.Name, err)
+		}
+	}
+	return nil
+}
+
+// Get write health, the write is healthy when the writer does not implement Healther.
+func (this *Write) Health() error {
+	if this.Config == nil {
+		return errors.New("config: must not be empty")
+	}
+	if healther, ok := this.Config.(Healther); ok {
+		return healther.Health()
+	}
+	return nil
+}
+
+// Get level enabler, level is enabled only when the global enabler enabled and
+// in the range of write minimum and maximum level.
+func (this *Write) LevelEnabler(global zapcore.LevelEnabler) zapcore.LevelEnabler {
+	min, max := this.Level, this.MaxLevel
+
+	return zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		if global != nil && !global.Enabled(level) {
+			return false
+		}
+		if min != nil && level < *min {
+			return false
+		}
+		if max != nil && level > *max {
+			return false
+		}
+		return true
+	})
+}
+
+// parse level from unmarshal value.
+func parseLevel(value interface{}) (*zapcore.Level, error) {
+	var level zapcore.Level
+
+	if err := level.UnmarshalText([]byte(fmt.Sprintf("%v", value))); err != nil {
+		return nil, err
+	}
+
+	return &level, nil
+}
+
 // unmarshal map[string]interface{}) data, get the name and config is exist,
 // the Writer which implement structure should be tag as `json:",inline" yaml:",inline" mapstructure:",squash"` format.
+// string values are expanded with environment variables, see ExpandEnv.
 func (this *Write) unmarshal(data map[string]interface{}) error {
+	// expand environment variables.
+	expandValue(data)
+
 	// get write name.
 	if name, ok := data["name"]; ok {
 		switch v := name.(type) {
@@ -46,21 +204,51 @@ func (this *Write) unmarshal(data map[string]interface{}) error {
 		return errors.New("write should be have name filed")
 	}
 
-	// load Writer.
-	if writer, ok := gWriters[this.Name]; ok {
-		// if implement Cloner then new one.
-		if face, ok := writer.(Cloner); ok {
-			this.Config = face.Clone()
-		} else {
-			this.Config = writer
+	// new Writer.
+	writer, err := NewWriter(this.Name)
+	if err != nil {
+		return err
+	}
+	this.Config = writer
+
+	// get write level range.
+	if level, ok := data["level"]; ok && level != nil {
+		l, err := parseLevel(level)
+		if err != nil {
+			return err
+		}
+		this.Level = l
+	}
+	if level, ok := data["max_level"]; ok && level != nil {
+		l, err := parseLevel(level)
+		if err != nil {
+			return err
+		}
+		this.MaxLevel = l
+	}
+
+	// get write encoding.
+	if encoding, ok := data["encoding"]; ok && encoding != nil {
+		this.Encoding = fmt.Sprintf("%v", encoding)
+		if err := encoder.CheckEncoding(this.Encoding); err != nil {
+			return err
+		}
+	}
+
+	// get write encoder overrides.
+	if config, ok := data["encoder"]; ok && config != nil {
+		this.Encoder = &encoder.Config{}
+		if err := mapstructure.Decode(config, this.Encoder); err != nil {
+			return err
+		}
+		if err := this.Encoder.Check(); err != nil {
+			return err
 		}
-	} else {
-		return errors.New("not support write: " + this.Name)
 	}
 
 	// if have config filed then parse it.
 	if config, ok := data["config"]; ok {
-		err := mapstructure.Decode(config, this.Config)
+		err := decode(config, this.Config)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,507 @@
+package syslog
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/syncer/internal/backoff"
+	"github.com/go-framework/zap/syncer/internal/entry"
+)
+
+const (
+	// Name.
+	Name = "syslog"
+
+	// Default network.
+	Network = "udp"
+	// Default address.
+	Address = "localhost:514"
+	// Default facility.
+	Facility = "user"
+	// Time allowed to dial and write a message.
+	Timeout = 5 * time.Second
+)
+
+// Syslog message formats.
+const (
+	// RFC 5424 format.
+	RFC5424 = "5424"
+	// RFC 3164 (BSD) format.
+	RFC3164 = "3164"
+)
+
+// Stream framings.
+const (
+	// Octet counting framing of RFC 6587, the message is prefixed with its length.
+	OctetCounting = "octet-counting"
+	// Non-transparent framing of RFC 6587, the message is terminated by a line feed.
+	NonTransparent = "non-transparent"
+)
+
+// Facility codes by name.
+var facilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// Local hostname.
+var hostname, _ = os.Hostname()
+
+// Syslog logger sends every zap entry as a syslog message, the severity is mapped from the entry level.
+// The connection is redialed on failure, writes fail fast until the next retry after backoff.
+type Logger struct {
+	// Network: udp, tcp, tls, unix or unixgram.
+	Network string `json:"network" yaml:"network" mapstructure:"network"`
+	// Syslog server address as host:port, or socket path for unix networks, e.g. /dev/log.
+	Address string `json:"address" yaml:"address" mapstructure:"address"`
+	// Facility: kern, user, mail, daemon, auth, syslog, lpr, news, uucp, cron, authpriv, ftp and local0 to local7.
+	Facility string `json:"facility" yaml:"facility" mapstructure:"facility"`
+	// Application name, default is the program name.
+	AppName string `json:"app_name" yaml:"app_name" mapstructure:"app_name"`
+	// Hostname, default is the local hostname.
+	Hostname string `json:"hostname" yaml:"hostname" mapstructure:"hostname"`
+	// Message format: 5424 or 3164.
+	Format string `json:"format" yaml:"format" mapstructure:"format"`
+	// Stream framing: octet-counting or non-transparent,
+	// default is octet-counting for tcp and tls, non-transparent for unix.
+	Framing string `json:"framing" yaml:"framing" mapstructure:"framing"`
+	// TLS CA certificate file, default is the system pool.
+	CA string `json:"ca" yaml:"ca" mapstructure:"ca"`
+	// TLS client certificate file.
+	Cert string `json:"cert" yaml:"cert" mapstructure:"cert"`
+	// TLS client key file.
+	Key string `json:"key" yaml:"key" mapstructure:"key"`
+	// TLS server name, default is the host of address.
+	ServerName string `json:"server_name" yaml:"server_name" mapstructure:"server_name"`
+	// TLS skip server certificate verification.
+	InsecureSkipVerify bool `json:"insecure_skip_verify" yaml:"insecure_skip_verify" mapstructure:"insecure_skip_verify"`
+	// Time allowed to dial and write a message.
+	Timeout time.Duration `json:"timeout" yaml:"timeout" mapstructure:"timeout"`
+	// Minimum redial backoff.
+	MinBackoff time.Duration `json:"min_backoff" yaml:"min_backoff" mapstructure:"min_backoff"`
+	// Maximum redial backoff.
+	MaxBackoff time.Duration `json:"max_backoff" yaml:"max_backoff" mapstructure:"max_backoff"`
+
+	// entry parser by the write encoder config.
+	parser entry.Parser
+
+	mutex   sync.Mutex
+	conn    net.Conn
+	closed  bool
+	backoff backoff.Backoff
+	// redial is not allowed before retry time.
+	retry time.Time
+	// last error.
+	err error
+}
+
+// New logger with network and address.
+func New(network, address string) *Logger {
+	l := GetDefault()
+	l.Network = network
+	l.Address = address
+	return l
+}
+
+// Get default logger.
+func GetDefault() *Logger {
+	return &Logger{
+		Network:    Network,
+		Address:    Address,
+		Facility:   Facility,
+		Format:     RFC5424,
+		Timeout:    Timeout,
+		MinBackoff: backoff.DefaultMin,
+		MaxBackoff: backoff.DefaultMax,
+	}
+}
+
+// Implement Cloner interface, only the config is copied, the clone sends by its own connection.
+func (l *Logger) Clone() io.Writer {
+	return &Logger{
+		Network:            l.Network,
+		Address:            l.Address,
+		Facility:           l.Facility,
+		AppName:            l.AppName,
+		Hostname:           l.Hostname,
+		Format:             l.Format,
+		Framing:            l.Framing,
+		CA:                 l.CA,
+		Cert:               l.Cert,
+		Key:                l.Key,
+		ServerName:         l.ServerName,
+		InsecureSkipVerify: l.InsecureSkipVerify,
+		Timeout:            l.Timeout,
+		MinBackoff:         l.MinBackoff,
+		MaxBackoff:         l.MaxBackoff,
+	}
+}
+
+// Implement syncer EntryParser interface, json entries are parsed and console entries are sent as is.
+func (l *Logger) Encoding() string {
+	return ""
+}
+
+// Implement syncer EntryParser interface.
+func (l *Logger) SetEncoderConfig(config zapcore.EncoderConfig) {
+	l.parser.SetEncoderConfig(config)
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	switch l.Network {
+	case "udp", "udp4", "udp6", "tcp", "tcp4", "tcp6", "tls", "unix", "unixgram":
+	default:
+		errs = append(errs, fmt.Errorf("network: not support %q", l.Network))
+	}
+	if l.Address == "" {
+		errs = append(errs, errors.New("address: must not be empty"))
+	}
+	if _, ok := facilities[l.Facility]; !ok {
+		errs = append(errs, fmt.Errorf("facility: not support %q", l.Facility))
+	}
+	if l.Format != RFC5424 && l.Format != RFC3164 {
+		errs = append(errs, fmt.Errorf("format: should be %s or %s", RFC5424, RFC3164))
+	}
+	switch l.Framing {
+	case "", OctetCounting, NonTransparent:
+	default:
+		errs = append(errs, fmt.Errorf("framing: should be %s or %s", OctetCounting, NonTransparent))
+	}
+	if (l.Cert == "") != (l.Key == "") {
+		errs = append(errs, errors.New("cert: cert and key should be set together"))
+	}
+	if l.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout: must be positive"))
+	}
+	if l.MaxBackoff < l.MinBackoff {
+		errs = append(errs, errors.New("max_backoff: must not be less than min_backoff"))
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return errors.New("syslog logger is closed")
+	}
+
+	return l.err
+}
+
+// Implement Writer interface, p should be a zap json or console entry.
+func (l *Logger) Write(p []byte) (n int, err error) {
+	message := l.message(p, time.Now())
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return 0, errors.New("syslog logger is closed")
+	}
+
+	if l.conn == nil {
+		if time.Now().Before(l.retry) {
+			return 0, l.err
+		}
+		if err := l.dial(); err != nil {
+			return 0, l.fail(err)
+		}
+	}
+
+	if err := l.send(message); err != nil {
+		// the connection may be closed by the server, redial and send once more.
+		l.conn.Close()
+		l.conn = nil
+		if err = l.dial(); err == nil {
+			err = l.send(message)
+		}
+		if err != nil {
+			return 0, l.fail(err)
+		}
+	}
+
+	l.backoff.Reset()
+	l.err = nil
+
+	return len(p), nil
+}
+
+// Implement WriteSyncer interface, messages are sent by Write.
+func (l *Logger) Sync() error {
+	return nil
+}
+
+// Close connection.
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.closed {
+		return errors.New("syslog logger is already closed")
+	}
+	l.closed = true
+
+	if l.conn == nil {
+		return nil
+	}
+
+	err := l.conn.Close()
+	l.conn = nil
+
+	return err
+}
+
+// record error and close the connection, redial is allowed after backoff.
+func (l *Logger) fail(err error) error {
+	if l.conn != nil {
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	l.backoff.Min, l.backoff.Max = l.MinBackoff, l.MaxBackoff
+	l.retry = time.Now().Add(l.backoff.Next())
+	l.err = err
+
+	return err
+}
+
+// get timeout.
+func (l *Logger) timeout() time.Duration {
+	if l.Timeout > 0 {
+		return l.Timeout
+	}
+	return Timeout
+}
+
+// is network a stream.
+func (l *Logger) stream() bool {
+	switch l.Network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return false
+	}
+	return true
+}
+
+// get stream framing.
+func (l *Logger) framing() string {
+	if l.Framing != "" {
+		return l.Framing
+	}
+	if l.Network == "unix" {
+		return NonTransparent
+	}
+	return OctetCounting
+}
+
+// dial syslog server, should be called with lock.
+func (l *Logger) dial() error {
+	dialer := &net.Dialer{Timeout: l.timeout()}
+
+	if l.Network != "tls" {
+		conn, err := dialer.Dial(l.Network, l.Address)
+		if err != nil {
+			return err
+		}
+		l.conn = conn
+		return nil
+	}
+
+	config, err := l.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	conn, err := tls.DialWithDialer(dialer, "tcp", l.Address, config)
+	if err != nil {
+		return err
+	}
+	l.conn = conn
+
+	return nil
+}
+
+// get tls config.
+func (l *Logger) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         l.ServerName,
+		InsecureSkipVerify: l.InsecureSkipVerify,
+	}
+
+	if l.CA != "" {
+		data, err := ioutil.ReadFile(l.CA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("ca: no certificate found in %s", l.CA)
+		}
+		config.RootCAs = pool
+	}
+
+	if l.Cert != "" || l.Key != "" {
+		cert, err := tls.LoadX509KeyPair(l.Cert, l.Key)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// send message with framing, should be called with lock.
+func (l *Logger) send(message []byte) error {
+	if err := l.conn.SetWriteDeadline(time.Now().Add(l.timeout())); err != nil {
+		return err
+	}
+
+	if l.stream() {
+		switch l.framing() {
+		case NonTransparent:
+			message = append(message, '\n')
+		default:
+			message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+		}
+	}
+
+	_, err := l.conn.Write(message)
+
+	return err
+}
+
+// Map zap level to syslog severity.
+func Severity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	case zapcore.FatalLevel:
+		return 0
+	}
+	return 5
+}
+
+// Color escape of colored level encoders.
+var colorEscape = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// get level of console encoded line by the leading tab separated fields.
+func consoleLevel(line []byte) (zapcore.Level, bool) {
+	fields := bytes.SplitN(line, []byte{'\t'}, 4)
+	for i := 0; i < len(fields) && i < 3; i++ {
+		var level zapcore.Level
+		text := colorEscape.ReplaceAll(fields[i], nil)
+		if level.UnmarshalText(bytes.ToLower(text)) == nil {
+			return level, true
+		}
+	}
+	return zapcore.InfoLevel, false
+}
+
+// format syslog message of entry, the message content is the encoded entry without line ending.
+func (l *Logger) message(p []byte, now time.Time) []byte {
+	line := bytes.TrimRight(p, "\r\n")
+
+	level, t, name := zapcore.InfoLevel, now, ""
+	if len(line) > 0 && line[0] == '{' {
+		e := l.parser.Parse(line)
+		level, t, name = e.Level, e.Time, e.Logger
+	} else if lvl, ok := consoleLevel(line); ok {
+		level = lvl
+	}
+
+	facility, ok := facilities[l.Facility]
+	if !ok {
+		facility = facilities[Facility]
+	}
+	priority := facility*8 + Severity(level)
+
+	host := l.Hostname
+	if host == "" {
+		host = hostname
+	}
+	app := l.AppName
+	if app == "" {
+		app = filepath.Base(os.Args[0])
+	}
+
+	var buf bytes.Buffer
+	if l.Format == RFC3164 {
+		fmt.Fprintf(&buf, "<%d>%s %s %s[%d]: ", priority, t.Format(time.Stamp), header(host, 255), header(app, 32), os.Getpid())
+	} else {
+		msgID := header(name, 32)
+		fmt.Fprintf(&buf, "<%d>1 %s %s %s %d %s - ", priority, t.Format("2006-01-02T15:04:05.000000Z07:00"), header(host, 255), header(app, 48), os.Getpid(), msgID)
+	}
+	buf.Write(line)
+
+	return buf.Bytes()
+}
+
+// header field of printable ascii without space in max length, empty is nil value -.
+func header(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, s)
+
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
@@ -0,0 +1,278 @@
+package syslog
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// zap json entry line.
+const line = `{"level":"warn","ts":1600000000.5,"logger":"db","msg":"slow query","ms":120}` + "\n"
+
+func TestLogger_Message(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+
+	tests := []struct {
+		name   string
+		logger *Logger
+		p      string
+		expect string
+	}{
+		{
+			name:   "5424",
+			logger: &Logger{Facility: "user", Format: RFC5424, Hostname: "host", AppName: "app"},
+			p:      line,
+			expect: "<12>1 " + time.Unix(1600000000, 5e8).Format("2006-01-02T15:04:05.000000Z07:00") + " host app " + pid + " db - " + strings.TrimSpace(line),
+		},
+		{
+			name:   "3164",
+			logger: &Logger{Facility: "local0", Format: RFC3164, Hostname: "host", AppName: "app"},
+			p:      line,
+			expect: "<132>" + time.Unix(1600000000, 0).Format(time.Stamp) + " host app[" + pid + "]: " + strings.TrimSpace(line),
+		},
+		{
+			name:   "console",
+			logger: &Logger{Facility: "local7", Format: RFC3164, Hostname: "my host", AppName: "app"},
+			p:      "2020-09-13T12:26:40.500Z\t\x1b[31mERROR\x1b[0m\tdb\tfailed\n",
+			expect: "<187>" + time.Unix(0, 0).Format(time.Stamp) + " my_host app[" + pid + "]: 2020-09-13T12:26:40.500Z\t\x1b[31mERROR\x1b[0m\tdb\tfailed",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			message := string(test.logger.message([]byte(test.p), time.Unix(0, 0)))
+			if message != test.expect {
+				t.Errorf("message:\n%q\nexpect:\n%q", message, test.expect)
+			}
+		})
+	}
+}
+
+func TestSeverity(t *testing.T) {
+	expects := map[zapcore.Level]int{
+		zapcore.DebugLevel:  7,
+		zapcore.InfoLevel:   6,
+		zapcore.WarnLevel:   4,
+		zapcore.ErrorLevel:  3,
+		zapcore.DPanicLevel: 2,
+		zapcore.PanicLevel:  1,
+		zapcore.FatalLevel:  0,
+	}
+	for level, severity := range expects {
+		if s := Severity(level); s != severity {
+			t.Errorf("%s severity: %d, expect: %d", level, s, severity)
+		}
+	}
+}
+
+func TestLogger_Validate(t *testing.T) {
+	if err := GetDefault().Validate(); err != nil {
+		t.Errorf("default should be valid: %v", err)
+	}
+
+	l := GetDefault()
+	l.Network = "http"
+	l.Address = ""
+	l.Facility = "local9"
+	l.Format = "5425"
+	l.Framing = "none"
+	l.Cert = "cert.pem"
+	l.Timeout = 0
+	l.MinBackoff = time.Second
+	l.MaxBackoff = time.Millisecond
+
+	err := l.Validate()
+	if err == nil {
+		t.Fatal("logger should be invalid")
+	}
+	for _, expect := range []string{"network", "address", "facility", "format", "framing", "cert", "timeout", "max_backoff"} {
+		if !strings.Contains(err.Error(), expect+":") {
+			t.Errorf("error should contain %s: %v", expect, err)
+		}
+	}
+}
+
+func TestLogger_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	l := New("udp", conn.LocalAddr().String())
+	defer l.Close()
+
+	if _, err := l.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 2048)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message := string(buf[:n]); !strings.HasPrefix(message, "<12>1 ") || !strings.HasSuffix(message, strings.TrimSpace(line)) {
+		t.Errorf("message: %q", message)
+	}
+}
+
+// read octet counting framed message.
+func readOctetCounting(r *bufio.Reader) (string, error) {
+	length, err := r.ReadString(' ')
+	if err != nil {
+		return "", err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(length))
+	if err != nil {
+		return "", err
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
+
+func TestLogger_TCPReconnect(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	conns := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conns <- conn
+		}
+	}()
+
+	l := New("tcp", listener.Addr().String())
+	l.MinBackoff = time.Millisecond
+	l.MaxBackoff = 10 * time.Millisecond
+	defer l.Close()
+
+	for i := 0; i < 2; i++ {
+		if _, err := l.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn := <-conns
+	r := bufio.NewReader(conn)
+	for i := 0; i < 2; i++ {
+		message, err := readOctetCounting(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasSuffix(message, strings.TrimSpace(line)) {
+			t.Errorf("message: %q", message)
+		}
+	}
+
+	// the server closes the connection, writes reconnect.
+	conn.Close()
+
+	deadline := time.After(5 * time.Second)
+	for {
+		l.Write([]byte(line))
+
+		select {
+		case conn := <-conns:
+			defer conn.Close()
+			if _, err := readOctetCounting(bufio.NewReader(conn)); err != nil {
+				t.Fatal(err)
+			}
+			if err := l.Health(); err != nil {
+				t.Errorf("health: %v", err)
+			}
+			return
+		case <-deadline:
+			t.Fatal("logger should reconnect")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestLogger_Unix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "syslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "log.sock")
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	l := New("unix", path)
+	l.Format = RFC3164
+	defer l.Close()
+
+	if _, err := l.Write([]byte(line)); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	message, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(message, "<12>") || !strings.HasSuffix(message, strings.TrimSpace(line)+"\n") {
+		t.Errorf("message: %q", message)
+	}
+}
+
+func TestLogger_Unavailable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	l := New("tcp", address)
+	l.MinBackoff = time.Hour
+	l.MaxBackoff = time.Hour
+	defer l.Close()
+
+	if _, err := l.Write([]byte(line)); err == nil {
+		t.Fatal("write should fail")
+	}
+	if l.Health() == nil {
+		t.Error("logger should be unhealthy")
+	}
+
+	// fail fast before retry.
+	start := time.Now()
+	if _, err := l.Write([]byte(line)); err == nil {
+		t.Fatal("write should fail")
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("write should fail fast: %v", d)
+	}
+
+}
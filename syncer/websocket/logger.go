@@ -2,12 +2,17 @@ package websocket
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"go.uber.org/multierr"
+
+	"github.com/go-framework/zap/syncer/internal/redact"
 )
 
 const (
@@ -40,14 +45,15 @@ type Logger struct {
 	// websocket conn.
 	conn *websocket.Conn
 	open bool
-	// reconnect status.
-	reconnect bool
 	// have visitor.
 	haveVisitor bool
 	exit        chan struct{}
 	output      chan *envelope
-	waiting     chan struct{}
-	rwMutex     *sync.RWMutex
+	// flush request to write pump, the reply is closed when the buffered envelopes are sent.
+	flush     chan chan struct{}
+	shutdown  bool
+	rwMutex   *sync.RWMutex
+	startOnce *sync.Once
 
 	// connect handler.
 	connectHandler ConnectHandler
@@ -79,12 +85,14 @@ func New(url string) *Logger {
 		MaxMessageSize:    MaxMessageSize,
 		MessageBufferSize: MessageBufferSize,
 		output:            make(chan *envelope, MessageBufferSize),
+		flush:             make(chan chan struct{}),
 		rwMutex:           &sync.RWMutex{},
+		startOnce:         &sync.Once{},
 		exit:              make(chan struct{}),
 	}
 
 	// go start connect.
-	go l.connect()
+	_ = l.Start()
 
 	return l
 }
@@ -99,37 +107,107 @@ func GetDefault() *Logger {
 		MaxMessageSize:    MaxMessageSize,
 		MessageBufferSize: MessageBufferSize,
 		output:            make(chan *envelope, MessageBufferSize),
+		flush:             make(chan chan struct{}),
 		rwMutex:           &sync.RWMutex{},
+		startOnce:         &sync.Once{},
 		exit:              make(chan struct{}),
 	}
 
 	return l
 }
 
-// Implement Cloner interface.
+// Implement Cloner interface, the clone is not started.
 func (l *Logger) Clone() io.Writer {
 	n := *l
 
+	n.conn = nil
+	n.open = false
+	n.shutdown = false
 	n.output = make(chan *envelope, l.MessageBufferSize)
+	n.flush = make(chan chan struct{})
 	n.rwMutex = &sync.RWMutex{}
+	n.startOnce = &sync.Once{}
 	n.exit = make(chan struct{})
 
-	// go start connect.
-	go n.connect()
-
 	return &n
 }
 
-// Waiting complete.
-func (l *Logger) Waiting() {
-	// waiting complete.
-	if l.waiting == nil {
-		l.waiting = make(chan struct{})
+// Implement syncer Redactor interface, the url credentials are masked.
+func (l *Logger) Redact() interface{} {
+	type config Logger
+
+	return &struct {
+		*config
+		Url string `json:"url" yaml:"url"`
+	}{
+		config: (*config)(l),
+		Url:    redact.URL(l.Url),
+	}
+}
+
+// Implement Starter interface, start connecting to the url once.
+func (l *Logger) Start() error {
+	if l.startOnce == nil {
+		return errors.New("websocket logger is not initialized, use New or GetDefault")
+	}
+
+	l.startOnce.Do(func() {
+		// resize the envelope buffer by the decoded config.
+		if l.MessageBufferSize > 0 && cap(l.output) != l.MessageBufferSize && len(l.output) == 0 {
+			l.output = make(chan *envelope, l.MessageBufferSize)
+		}
+
+		// go start connect.
+		go l.connect()
+	})
+
+	return nil
+}
+
+// Implement Validator interface.
+func (l *Logger) Validate() error {
+	var errs []error
+
+	if l.Url == "" {
+		errs = append(errs, errors.New("url: must not be empty"))
+	} else if u, err := url.Parse(l.Url); err != nil {
+		errs = append(errs, fmt.Errorf("url: %v", err))
+	} else if u.Scheme != "ws" && u.Scheme != "wss" {
+		errs = append(errs, fmt.Errorf("url: scheme should be ws or wss, got %q", u.Scheme))
+	}
+	if l.WriteWait <= 0 {
+		errs = append(errs, errors.New("write_wait: must be positive"))
+	}
+	if l.PongWait <= 0 {
+		errs = append(errs, errors.New("pong_wait: must be positive"))
+	}
+	if l.PingPeriod <= 0 || l.PingPeriod >= l.PongWait {
+		errs = append(errs, errors.New("ping_period: must be positive and less than pong_wait"))
+	}
+	if l.MaxMessageSize <= 0 {
+		errs = append(errs, errors.New("max_message_size: must be positive"))
+	}
+	if l.MessageBufferSize <= 0 {
+		errs = append(errs, errors.New("message_buffer_size: must be positive"))
+	}
 
+	return multierr.Combine(errs...)
+}
+
+// Implement Healther interface.
+func (l *Logger) Health() error {
+	if l.rwMutex == nil || l.closed() {
+		return fmt.Errorf("not connected to %s", l.Url)
 	}
-	<-l.waiting
-	close(l.waiting)
-	l.waiting = nil
+	if cap(l.output) > 0 && len(l.output) == cap(l.output) {
+		return errors.New("envelope buffer is full")
+	}
+	return nil
+}
+
+// Waiting the buffered envelopes sent, bounded by write wait.
+func (l *Logger) Waiting() {
+	_ = l.wait()
 }
 
 // Implement Writer interface.
@@ -154,26 +232,63 @@ func (l *Logger) Write(p []byte) (n int, err error) {
 	return
 }
 
-// Close.
+// Implement WriteSyncer interface, waiting the buffered envelopes sent, bounded by write wait.
+func (l *Logger) Sync() error {
+	return l.wait()
+}
+
+// Close, the buffered envelopes are sent with bounded time when connected.
 func (l *Logger) Close() error {
-	if l.closed() {
+	if l.rwMutex == nil {
+		return errors.New("websocket logger is not initialized, use New or GetDefault")
+	}
+
+	l.rwMutex.Lock()
+	if l.shutdown {
+		l.rwMutex.Unlock()
 		return errors.New("websocket conn is already closed")
 	}
+	l.shutdown = true
+	l.rwMutex.Unlock()
 
-	defer func() {
+	// waiting buffered messages send complete.
+	err := l.wait()
+	if !l.closed() {
 		_ = l.write(&envelope{t: websocket.CloseMessage, data: nil})
-		l.close()
-	}()
+	}
+
+	l.close()
+
+	return err
+}
 
-	// waiting send complete.
-	if l.waiting == nil {
-		l.waiting = make(chan struct{})
+// waiting the buffered envelopes sent by write pump with write wait timeout.
+func (l *Logger) wait() error {
+	if l.flush == nil || l.closed() {
+		return nil
 	}
-	<-l.waiting
-	close(l.waiting)
-	l.waiting = nil
 
-	return nil
+	timeout := l.WriteWait
+	if timeout <= 0 {
+		timeout = WriteWait
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	reply := make(chan struct{})
+
+	select {
+	case l.flush <- reply:
+	case <-timer.C:
+		return fmt.Errorf("websocket flush timeout after %s", timeout)
+	}
+
+	select {
+	case <-reply:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("websocket flush timeout after %s", timeout)
+	}
 }
 
 // Set url.
@@ -212,18 +327,21 @@ func (l *Logger) connect() {
 				break
 			}
 
+			// closed while dialing.
+			l.rwMutex.Lock()
+			if l.shutdown {
+				_ = l.conn.Close()
+				l.rwMutex.Unlock()
+				return
+			}
 			l.open = true
+			l.rwMutex.Unlock()
 
 			// connect handler callback.
 			if l.connectHandler != nil {
 				l.connectHandler()
 			}
 
-			// send waiting signal.
-			if l.waiting != nil {
-				l.waiting <- struct{}{}
-			}
-
 			return
 		}
 	}
@@ -231,8 +349,8 @@ func (l *Logger) connect() {
 
 // dial to server.
 func (l *Logger) dial() (err error) {
-	// get websocket dialer
-	mDial := websocket.DefaultDialer
+	// copy websocket default dialer
+	mDial := *websocket.DefaultDialer
 	// set write buffer size
 	mDial.WriteBufferSize = int(l.MaxMessageSize)
 
@@ -278,9 +396,12 @@ func (l *Logger) write(message *envelope) error {
 
 // readPump pumps messages from the websocket connection.
 func (l *Logger) readPump() {
+	// reconnect when read failed.
+	reconnect := false
+
 	defer func() {
 		// restart.
-		if l.reconnect {
+		if reconnect {
 			l.restart()
 		}
 	}()
@@ -304,7 +425,7 @@ func (l *Logger) readPump() {
 		// read envelope.
 		_, data, err := l.conn.ReadMessage()
 		if err != nil {
-			l.reconnect = true
+			reconnect = true
 			return
 		}
 		// new message.
@@ -331,10 +452,13 @@ func (l *Logger) readPump() {
 func (l *Logger) writePump() {
 	ticker := time.NewTicker(l.PingPeriod)
 
+	// reconnect when write failed.
+	reconnect := false
+
 	defer func() {
 		ticker.Stop()
 		// restart.
-		if l.reconnect {
+		if reconnect {
 			l.restart()
 		}
 	}()
@@ -348,7 +472,7 @@ func (l *Logger) writePump() {
 		case <-ticker.C:
 			// ping.
 			if err := l.write(&envelope{t: websocket.PingMessage, data: nil}); err != nil {
-				l.reconnect = true
+				reconnect = true
 				return
 			}
 		case message, ok := <-l.output:
@@ -357,13 +481,31 @@ func (l *Logger) writePump() {
 			}
 			// write raw envelope.
 			if err := l.write(message); err != nil {
-				l.reconnect = true
+				reconnect = true
+				return
+			}
+		case reply := <-l.flush:
+			// send the buffered envelopes.
+			err := l.drain()
+			close(reply)
+			if err != nil {
+				reconnect = true
 				return
 			}
-			// waiting
-			if l.waiting != nil && len(l.output) == 0 {
-				l.waiting <- struct{}{}
+		}
+	}
+}
+
+// send the buffered envelopes.
+func (l *Logger) drain() error {
+	for {
+		select {
+		case message := <-l.output:
+			if err := l.write(message); err != nil {
+				return err
 			}
+		default:
+			return nil
 		}
 	}
 }
@@ -376,27 +518,28 @@ func (l *Logger) closed() bool {
 	return !l.open
 }
 
-// close.
+// close, stop connecting and the pumps, the output is not closed as Write may be called concurrently.
 func (l *Logger) close() {
-	if !l.closed() {
-		l.rwMutex.Lock()
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	if l.open {
 		l.open = false
-		close(l.exit)
 		_ = l.conn.Close()
-		close(l.output)
-		l.rwMutex.Unlock()
 	}
+	close(l.exit)
 }
 
 // restart.
 func (l *Logger) restart() {
-	if !l.closed() {
-		l.rwMutex.Lock()
+	l.rwMutex.Lock()
+	defer l.rwMutex.Unlock()
+
+	if l.open && !l.shutdown {
 		l.open = false
 		close(l.exit)
 		_ = l.conn.Close()
 		l.exit = make(chan struct{})
 		go l.connect()
-		l.rwMutex.Unlock()
 	}
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"io/ioutil"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	gorilla "github.com/gorilla/websocket"
 	zap2 "go.uber.org/zap"
 	"gopkg.in/yaml.v3"
 
@@ -118,3 +121,84 @@ func TestWebSocketLogger_ConfigFile(t *testing.T) {
 
 	WebSocketLoggerSyncer(t, config)
 }
+
+func TestLogger_CloseNotConnected(t *testing.T) {
+	write := websocket.New("ws://127.0.0.1:1/realtime/logger")
+	write.WriteOnVisitor = false
+
+	if _, err := write.Write([]byte("buffered\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- write.Close()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("close should not block when not connected")
+	}
+
+	if err := write.Close(); err == nil {
+		t.Error("close twice should fail")
+	}
+}
+
+func TestLogger_CloseFlush(t *testing.T) {
+	received := make(chan string, 10)
+	upgrader := gorilla.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			received <- string(data)
+		}
+	}))
+	defer server.Close()
+
+	connected := make(chan struct{})
+	write := websocket.GetDefault()
+	write.SetUrl("ws" + strings.TrimPrefix(server.URL, "http"))
+	write.WriteOnVisitor = false
+	write.SetConnectHandler(func() {
+		close(connected)
+	})
+	if err := write.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not connected")
+	}
+
+	for _, line := range []string{"first", "second"} {
+		if _, err := write.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := write.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expect := range []string{"first", "second"} {
+		select {
+		case data := <-received:
+			if data != expect {
+				t.Errorf("received %q, expect %q", data, expect)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s is not received", expect)
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package zap
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/go-framework/zap/collector"
+)
+
+// Default tail subscriber buffer size, entries are dropped when the buffer is full.
+const DefaultTailBufferSize = 256
+
+// Guard lazy initialization of tail hub.
+var tailHubMutex sync.Mutex
+
+// get tail hub of config.
+func (c *Config) tailHub() *tailHub {
+	tailHubMutex.Lock()
+	defer tailHubMutex.Unlock()
+
+	if c.tail == nil {
+		c.tail = &tailHub{subscribers: make(map[*tailSubscriber]struct{})}
+	}
+
+	return c.tail
+}
+
+// Implement LogServiceServer interface.
+
+// Tail the live log entries of logger, entries are dropped when the client is too slow.
+func (c *Config) Tail(req *TailRequest, stream LogService_TailServer) error {
+	level, err := LevelToZap(req.MinLevel)
+	if err != nil {
+		return err
+	}
+
+	hub := c.tailHub()
+
+	subscriber := hub.subscribe(level, req.Logger, req.Fields, DefaultTailBufferSize)
+	defer hub.unsubscribe(subscriber)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case entry := <-subscriber.entries:
+			if err := stream.Send(entry); err != nil {
+				return status.Error(codes.Unavailable, err.Error())
+			}
+		}
+	}
+}
+
+// Tail subscriber.
+type tailSubscriber struct {
+	// minimum entry level.
+	level zapcore.Level
+	// logger name prefix.
+	logger string
+	// fields should be equal.
+	fields map[string]string
+	// entries buffer.
+	entries chan *LogEntry
+	// dropped entries count.
+	dropped uint64
+}
+
+// match entry level and logger name.
+func (s *tailSubscriber) matchEntry(entry zapcore.Entry) bool {
+	if entry.Level < s.level {
+		return false
+	}
+	if s.logger == "" || entry.LoggerName == s.logger {
+		return true
+	}
+	return strings.HasPrefix(entry.LoggerName, s.logger+".")
+}
+
+// match entry fields.
+func (s *tailSubscriber) matchFields(fields map[string]string) bool {
+	for k, v := range s.fields {
+		if value, ok := fields[k]; !ok || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Tail hub, entries are published to subscribers, it costs nothing without subscriber.
+type tailHub struct {
+	// subscribers count.
+	count int32
+	// minimum level of subscribers.
+	level int32
+
+	mutex       sync.RWMutex
+	subscribers map[*tailSubscriber]struct{}
+}
+
+// subscribe entries.
+func (h *tailHub) subscribe(level zapcore.Level, logger string, fields map[string]string, size int) *tailSubscriber {
+	s := &tailSubscriber{
+		level:   level,
+		logger:  logger,
+		fields:  fields,
+		entries: make(chan *LogEntry, size),
+	}
+
+	h.mutex.Lock()
+	h.subscribers[s] = struct{}{}
+	h.update()
+	h.mutex.Unlock()
+
+	return s
+}
+
+// unsubscribe entries.
+func (h *tailHub) unsubscribe(s *tailSubscriber) {
+	h.mutex.Lock()
+	delete(h.subscribers, s)
+	h.update()
+	h.mutex.Unlock()
+}
+
+// update subscribers count and minimum level, should be called with lock.
+func (h *tailHub) update() {
+	level := zapcore.FatalLevel
+	for s := range h.subscribers {
+		if s.level < level {
+			level = s.level
+		}
+	}
+
+	atomic.StoreInt32(&h.level, int32(level))
+	atomic.StoreInt32(&h.count, int32(len(h.subscribers)))
+}
+
+// is level enabled by any subscriber.
+func (h *tailHub) enabled(level zapcore.Level) bool {
+	return atomic.LoadInt32(&h.count) > 0 && int32(level) >= atomic.LoadInt32(&h.level)
+}
+
+// publish entry to matched subscribers.
+func (h *tailHub) publish(entry zapcore.Entry, fields []zapcore.Field) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	var e *LogEntry
+	for s := range h.subscribers {
+		if !s.matchEntry(entry) {
+			continue
+		}
+
+		// new log entry once.
+		if e == nil {
+			e = newLogEntry(entry, fields)
+		}
+
+		if !s.matchFields(e.Fields) {
+			continue
+		}
+
+		select {
+		case s.entries <- e:
+		default:
+			atomic.AddUint64(&s.dropped, 1)
+		}
+	}
+}
+
+// new log entry, string field values are kept as is, others are json encoded.
+func newLogEntry(entry zapcore.Entry, fields []zapcore.Field) *LogEntry {
+	level, err := LevelFromZap(entry.Level)
+	if err != nil {
+		level = Level_Info
+	}
+
+	e := &LogEntry{
+		Time:    entry.Time.UnixNano(),
+		Level:   level,
+		Logger:  entry.LoggerName,
+		Message: entry.Message,
+		Stack:   entry.Stack,
+	}
+	if entry.Caller.Defined {
+		e.Caller = entry.Caller.TrimmedPath()
+	}
+
+	if len(fields) > 0 {
+		enc := zapcore.NewMapObjectEncoder()
+		for _, field := range fields {
+			field.AddTo(enc)
+		}
+
+		e.Fields = collector.FieldsOf(enc.Fields)
+	}
+
+	return e
+}
+
+// Tail core publishes entries to tail hub.
+type tailCore struct {
+	hub    *tailHub
+	fields []zapcore.Field
+}
+
+// new tail core.
+func newTailCore(hub *tailHub) zapcore.Core {
+	return &tailCore{hub: hub}
+}
+
+// Implement zapcore LevelEnabler interface.
+func (t *tailCore) Enabled(level zapcore.Level) bool {
+	return t.hub.enabled(level)
+}
+
+// Implement zapcore Core interface.
+func (t *tailCore) With(fields []zapcore.Field) zapcore.Core {
+	fs := make([]zapcore.Field, 0, len(t.fields)+len(fields))
+	fs = append(fs, t.fields...)
+	fs = append(fs, fields...)
+	return &tailCore{hub: t.hub, fields: fs}
+}
+
+// Implement zapcore Core interface.
+func (t *tailCore) Check(entry zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if t.Enabled(entry.Level) {
+		return ce.AddCore(entry, t)
+	}
+	return ce
+}
+
+// Implement zapcore Core interface.
+func (t *tailCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	if len(t.fields) > 0 {
+		fs := make([]zapcore.Field, 0, len(t.fields)+len(fields))
+		fs = append(fs, t.fields...)
+		fields = append(fs, fields...)
+	}
+
+	t.hub.publish(entry, fields)
+
+	return nil
+}
+
+// Implement zapcore Core interface.
+func (t *tailCore) Sync() error {
+	return nil
+}
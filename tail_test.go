@@ -0,0 +1,89 @@
+package zap
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestConfig_Tail(t *testing.T) {
+	config := &Config{Level: zap.NewAtomicLevelAt(zap.InfoLevel)}
+	logger := config.NewZapLogger()
+
+	client := NewLogServiceClient(dialLevelServer(t, config))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.Tail(ctx, &TailRequest{
+		MinLevel: Level_Warn,
+		Logger:   "db",
+		Fields:   map[string]string{"user": "alice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// waiting subscribed.
+	hub := config.tailHub()
+	for i := 0; atomic.LoadInt32(&hub.count) == 0; i++ {
+		if i > 100 {
+			t.Fatal("tail is not subscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	logger.Named("db").Info("level filtered", zap.String("user", "alice"))
+	logger.Named("dbx").Warn("logger filtered", zap.String("user", "alice"))
+	logger.Named("db").Warn("field filtered", zap.String("user", "bob"))
+	logger.Named("db").With(zap.String("user", "alice")).Named("sql").Error("matched", zap.Int("count", 3))
+
+	entry, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Message != "matched" || entry.Logger != "db.sql" || entry.Level != Level_Error {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if entry.Fields["user"] != "alice" || entry.Fields["count"] != "3" {
+		t.Errorf("unexpected entry fields %v", entry.Fields)
+	}
+	if entry.Caller == "" || entry.Time == 0 {
+		t.Errorf("expect entry caller and time %v", entry)
+	}
+
+	// invalid level.
+	stream, err = client.Tail(ctx, &TailRequest{MinLevel: Level(9)})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if err == nil {
+		t.Error("expect invalid level error")
+	}
+
+	// unsubscribed when canceled.
+	cancel()
+	for i := 0; atomic.LoadInt32(&hub.count) != 0; i++ {
+		if i > 100 {
+			t.Fatal("tail is not unsubscribed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestTailCore_Disabled(t *testing.T) {
+	core := newTailCore(&tailHub{subscribers: make(map[*tailSubscriber]struct{})})
+
+	for level := zapcore.DebugLevel; level <= zapcore.FatalLevel; level++ {
+		if core.Enabled(level) {
+			t.Errorf("expect %s disabled without subscriber", level)
+		}
+		if ce := core.Check(zapcore.Entry{Level: level}, nil); ce != nil {
+			t.Errorf("expect %s not checked without subscriber", level)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package zap
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// Validate config, errors are prefixed by field path as "writes[1].config.url: message",
+// multiple errors are combined by go.uber.org/multierr.
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Level == (zap.AtomicLevel{}) {
+		errs = append(errs, errors.New("level: must be set"))
+	}
+
+	if err := c.Encoder.Check(); err != nil {
+		errs = append(errs, fmt.Errorf("encoder: %v", err))
+	}
+
+	if c.Sampling != nil {
+		if c.Sampling.Tick < 0 {
+			errs = append(errs, errors.New("sampling.tick: must not be negative"))
+		}
+		if c.Sampling.Initial < 0 {
+			errs = append(errs, errors.New("sampling.initial: must not be negative"))
+		}
+		if c.Sampling.Thereafter < 0 {
+			errs = append(errs, errors.New("sampling.thereafter: must not be negative"))
+		}
+	}
+
+	if c.Caller != nil && c.Caller.Skip < 0 {
+		errs = append(errs, errors.New("caller.skip: must not be negative"))
+	}
+
+	for i, write := range c.Writes {
+		if write == nil {
+			errs = append(errs, fmt.Errorf("writes[%d]: must not be empty", i))
+			continue
+		}
+		for _, err := range multierr.Errors(write.Validate()) {
+			errs = append(errs, fmt.Errorf("writes[%d].%v", i, err))
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
+
+// Build zap logger, return error when config is invalid.
+func (c *Config) Build(opts ...zap.Option) (*zap.Logger, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c.NewZapLogger(opts...), nil
+}
@@ -0,0 +1,105 @@
+package zap
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v2"
+
+	"github.com/go-framework/zap/encoder"
+	"github.com/go-framework/zap/syncer"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	config := &Config{}
+	err := yaml.Unmarshal([]byte(`
+level: info
+sampling:
+  initial: -1
+writes:
+  - name: lumberjack
+    level: error
+    max_level: info
+    config:
+      filename: test.log
+      maxsize: -1
+  - name: websocket
+    config:
+      url: ""
+`), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = config.Validate()
+	if err == nil {
+		t.Fatal("config should be invalid")
+	}
+
+	t.Log(err)
+
+	expects := []string{
+		"sampling.initial:",
+		"writes[0].config.maxsize:",
+		"writes[0].max_level:",
+		"writes[1].config.url:",
+	}
+	errs := multierr.Errors(err)
+	if len(errs) != len(expects) {
+		t.Fatalf("expect %d errors, got %d: %v", len(expects), len(errs), err)
+	}
+	for i, expect := range expects {
+		if !strings.HasPrefix(errs[i].Error(), expect) {
+			t.Errorf("error %d expect prefix %q, got %q", i, expect, errs[i])
+		}
+	}
+
+	if logger, err := config.Build(); err == nil || logger != nil {
+		t.Fatal("build should be failed with invalid config")
+	}
+
+	if _, err := Build(GetDebugConfig(), zap.AddCallerSkip(1)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWrite_UnmarshalYAML(t *testing.T) {
+	config := &Config{}
+	err := yaml.Unmarshal([]byte(`
+writes:
+  - name: unknown
+`), config)
+	if err == nil || !strings.Contains(err.Error(), "lumberjack") {
+		t.Fatalf("unknown write error should list supported writes: %v", err)
+	}
+}
+
+func TestConfig_ValidateEncoder(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	config := GetDebugConfig()
+	config.Console = false
+	config.SetEncoder(&encoder.Config{MessageKey: "message", TimeEncoder: "unknown", LevelEncoder: "upper"})
+	config.AddSyncerWrite((&syncer.Write{Config: buf}).SetEncoding(encoder.JSON).SetEncoder(&encoder.Config{CallerEncoder: "long", LevelKey: "severity"}))
+
+	err := config.Validate()
+	for _, expect := range []string{"encoder: ", "time encoder: unknown", "level encoder: upper", "writes[0].encoder: ", "caller encoder: long"} {
+		if err == nil || !strings.Contains(err.Error(), expect) {
+			t.Errorf("error should contain %q: %v", expect, err)
+		}
+	}
+
+	core, err := config.newCore()
+	if err == nil || !strings.Contains(err.Error(), "writes[0].encoder: not support caller encoder: long") {
+		t.Errorf("new core should report the write encoder error: %v", err)
+	}
+
+	// the supported overrides are applied.
+	zap.New(core).Info("hello")
+	if !strings.Contains(buf.String(), `"severity":"INFO"`) || !strings.Contains(buf.String(), `"message":"hello"`) {
+		t.Errorf("supported overrides should be applied: %s", buf.String())
+	}
+}
@@ -0,0 +1,274 @@
+package zap
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/json-iterator/go"
+	"go.uber.org/zap"
+)
+
+const (
+	// Default config file watch interval.
+	DefaultWatchInterval = time.Second
+)
+
+// Config watcher, polls the config file and reloads the live logger when the file changed.
+// Level, levels, writes, fields, encoder and sampling are reloaded in place,
+// development, caller and stacktrace options are only applied to new loggers.
+type ConfigWatcher struct {
+	// config file path.
+	path string
+	// poll interval.
+	interval time.Duration
+	// last file stat and content.
+	modTime time.Time
+	size    int64
+	data    []byte
+	// current config.
+	config *Config
+	// swappable core of logger.
+	core   *swapCore
+	logger *zap.Logger
+
+	mutex *sync.Mutex
+	exit  chan struct{}
+	once  *sync.Once
+}
+
+// Watch config file with default interval, return the watcher which hold the live logger.
+func Watch(path string, opts ...zap.Option) (*ConfigWatcher, error) {
+	return NewConfigWatcher(path, DefaultWatchInterval, opts...)
+}
+
+// New config watcher, load the config file and start polling it with interval.
+func NewConfigWatcher(path string, interval time.Duration, opts ...zap.Option) (*ConfigWatcher, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := ParseConfig(path, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	w := &ConfigWatcher{
+		path:     path,
+		interval: interval,
+		modTime:  info.ModTime(),
+		size:     info.Size(),
+		data:     data,
+		config:   config,
+		mutex:    &sync.Mutex{},
+		exit:     make(chan struct{}),
+		once:     &sync.Once{},
+	}
+
+	core, err := config.newCore()
+	if err != nil {
+		_ = config.Close()
+		return nil, err
+	}
+
+	w.core = newSwapCore(core)
+	w.logger = zap.New(w.core).WithOptions(config.newOptions(opts)...)
+
+	// go start watch.
+	go w.watch()
+
+	return w, nil
+}
+
+// Get the live logger.
+func (w *ConfigWatcher) Logger() *zap.Logger {
+	return w.logger
+}
+
+// Get current config.
+func (w *ConfigWatcher) Config() *Config {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.config
+}
+
+// Reload config file now.
+func (w *ConfigWatcher) Reload() error {
+	data, err := ioutil.ReadFile(w.path)
+	if err != nil {
+		w.logger.Error("logger config reload failed", zap.String("path", w.path), zap.Error(err))
+		return err
+	}
+
+	return w.reload(data)
+}
+
+// Rotate the writes of current config, the writes are not closed by reload while rotating.
+func (w *ConfigWatcher) Rotate() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.config.Rotate()
+}
+
+// Stop watching, sync the logger and close writes of current config.
+func (w *ConfigWatcher) Close() error {
+	w.once.Do(func() {
+		close(w.exit)
+	})
+
+	_ = w.logger.Sync()
+
+	return w.Config().Close()
+}
+
+// watch config file.
+func (w *ConfigWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+
+	defer func() {
+		ticker.Stop()
+	}()
+
+	for {
+		select {
+		case <-w.exit:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(w.path)
+			if err != nil {
+				continue
+			}
+
+			w.mutex.Lock()
+			changed := !info.ModTime().Equal(w.modTime) || info.Size() != w.size
+			w.modTime, w.size = info.ModTime(), info.Size()
+			w.mutex.Unlock()
+
+			if changed {
+				_ = w.Reload()
+			}
+		}
+	}
+}
+
+// reload config data, invalid data is rejected and the running logger is not disturbed.
+func (w *ConfigWatcher) reload(data []byte) error {
+	w.mutex.Lock()
+
+	// not changed.
+	if bytes.Equal(data, w.data) {
+		w.mutex.Unlock()
+		return nil
+	}
+
+	config, err := ParseConfig(w.path, data)
+	if err == nil {
+		err = config.Validate()
+	}
+	if err != nil {
+		w.mutex.Unlock()
+		w.logger.Error("logger config reload failed", zap.String("path", w.path), zap.Error(err))
+		return err
+	}
+
+	old := w.config
+	changes := diffConfig(old, config)
+
+	// keep the level overrides and tail subscribers.
+	config.overrides = old.levelOverrides()
+	config.tail = old.tailHub()
+
+	// keep the atomic level and level tree in place, the new values are set after the core is built,
+	// so the running logger keeps its levels when the core fails.
+	level := config.Level.Level()
+	levels := config.Levels.Levels()
+	config.Level = old.Level
+	config.Levels = old.Levels
+
+	core, err := config.newCore()
+	if err != nil {
+		w.mutex.Unlock()
+		_ = config.Close()
+		w.logger.Error("logger config reload failed", zap.String("path", w.path), zap.Error(err))
+		return err
+	}
+
+	// the active level overrides are rebased on the reloaded levels.
+	config.overrides.reset(config, level, levels)
+
+	// swap writes and fields.
+	oldState := w.core.swap(core)
+
+	w.config = config
+	w.data = data
+
+	w.mutex.Unlock()
+
+	// wait the in-flight writes of old core, then flush and release old writes.
+	if !oldState.wait(DefaultCloseTimeout) {
+		w.logger.Warn("logger config reload timeout waiting old writes", zap.String("path", w.path), zap.Duration("timeout", DefaultCloseTimeout))
+	}
+	_ = oldState.core.Sync()
+	if err := old.Close(); err != nil {
+		w.logger.Error("logger config close old writes failed", zap.String("path", w.path), zap.Error(err))
+	}
+
+	w.logger.Info("logger config reloaded", zap.String("path", w.path), zap.Strings("changes", changes))
+
+	return nil
+}
+
+// describe config changes.
+func diffConfig(old, new *Config) (changes []string) {
+	if o, n := old.Level.Level(), new.Level.Level(); o != n {
+		changes = append(changes, fmt.Sprintf("level: %s -> %s", o, n))
+	}
+	if old.Development != new.Development {
+		changes = append(changes, fmt.Sprintf("development: %t -> %t", old.Development, new.Development))
+	}
+	if old.Console != new.Console {
+		changes = append(changes, fmt.Sprintf("console: %t -> %t", old.Console, new.Console))
+	}
+
+	// compare json encoded values.
+	diff := func(name string, o, n interface{}) {
+		od, _ := jsoniter.MarshalToString(o)
+		nd, _ := jsoniter.MarshalToString(n)
+		if od != nd {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", name, od, nd))
+		}
+	}
+
+	// the writer secrets are not logged.
+	diff("writes", old.redacted().Writes, new.redacted().Writes)
+	diff("fields", old.Fields, new.Fields)
+	diff("encoder", old.Encoder, new.Encoder)
+	diff("sampling", old.Sampling, new.Sampling)
+	diff("caller", old.Caller, new.Caller)
+	diff("stacktrace_level", old.StacktraceLevel, new.StacktraceLevel)
+	diff("levels", old.Levels, new.Levels)
+
+	if old.DisableStacktrace != new.DisableStacktrace {
+		changes = append(changes, fmt.Sprintf("disable_stacktrace: %t -> %t", old.DisableStacktrace, new.DisableStacktrace))
+	}
+
+	return
+}
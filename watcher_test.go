@@ -0,0 +1,287 @@
+package zap
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/go-framework/zap/syncer"
+)
+
+func writeFile(t *testing.T, filename, data string) {
+	if err := ioutil.WriteFile(filename, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readFile(t *testing.T, filename string) string {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	a := filepath.Join(dir, "a.log")
+	b := filepath.Join(dir, "b.log")
+
+	writeFile(t, filename, `
+level: info
+writes:
+  - name: lumberjack
+    config:
+      filename: `+a)
+
+	w, err := NewConfigWatcher(filename, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	level := w.Config().Level
+	logger := w.Logger().With(zap.String("with", "field"))
+
+	logger.Debug("debug before reload")
+	logger.Info("info before reload")
+
+	// invalid config is rejected.
+	writeFile(t, filename, "level: [")
+	if err := w.Reload(); err == nil {
+		t.Fatal("invalid config should be rejected")
+	}
+
+	writeFile(t, filename, `
+level: debug
+fields:
+  app: test
+writes:
+  - name: lumberjack
+    config:
+      filename: `+b)
+
+	// waiting watcher reload.
+	for i := 0; i < 100 && level.Level() != zap.DebugLevel; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if level.Level() != zap.DebugLevel {
+		t.Fatal("atomic level should be updated in place")
+	}
+
+	logger.Debug("debug after reload")
+
+	if data := readFile(t, a); strings.Contains(data, "debug") || !strings.Contains(data, "info before reload") || !strings.Contains(data, "reload failed") {
+		t.Fatalf("unexpected a.log: %s", data)
+	}
+	if data := readFile(t, b); !strings.Contains(data, "debug after reload") || !strings.Contains(data, `"app":"test"`) || !strings.Contains(data, `"with":"field"`) {
+		t.Fatalf("unexpected b.log: %s", data)
+	}
+}
+
+// Writer fails to start.
+type failStartWriter struct{}
+
+func (w *failStartWriter) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (w *failStartWriter) Start() error {
+	return errors.New("start failed")
+}
+
+func TestConfigWatcher_ReloadStartFailed(t *testing.T) {
+	syncer.RegisterWriter("fail_start", &failStartWriter{})
+	defer syncer.Unregister("fail_start")
+
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	writeFile(t, filename, `
+level: info
+levels:
+  db: warn
+writes:
+  - name: lumberjack
+    config:
+      filename: `+filepath.Join(dir, "a.log"))
+
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// the writer of the new config fails to start.
+	writeFile(t, filename, `
+level: debug
+levels:
+  db: error
+  http: debug
+writes:
+  - name: fail_start`)
+	if err := w.Reload(); err == nil || !strings.Contains(err.Error(), "start failed") {
+		t.Fatalf("reload should fail by start: %v", err)
+	}
+
+	config := w.Config()
+	if level := config.Level.Level(); level != zap.InfoLevel {
+		t.Errorf("level should be kept, got %s", level)
+	}
+	if levels := config.Levels.Levels(); len(levels) != 1 || levels["db"] != zap.WarnLevel {
+		t.Errorf("levels should be kept, got %v", levels)
+	}
+}
+
+func TestConfigWatcher_ReloadRedacted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	a := filepath.Join(dir, "a.log")
+	config := func(port, password string) string {
+		return `
+writes:
+  - name: lumberjack
+    config:
+      filename: ` + a + `
+  - name: http
+    level: fatal
+    config:
+      url: http://127.0.0.1:` + port + `
+      password: ` + password
+	}
+
+	writeFile(t, filename, config("1", "first-secret"))
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	writeFile(t, filename, config("2", "second-secret"))
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the writes change is logged without the password.
+	if data := readFile(t, a); !strings.Contains(data, "127.0.0.1:2") || !strings.Contains(data, syncer.Redacted) || strings.Contains(data, "secret") {
+		t.Fatalf("reload log should contain redacted changes: %s", data)
+	}
+}
+
+func TestConfigWatcher_ReloadOverrides(t *testing.T) {
+	dir, err := ioutil.TempDir("", "zap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "config.yaml")
+	config := func(root, db string) string {
+		return `
+level: ` + root + `
+levels:
+  db: ` + db + `
+  http: info
+writes:
+  - name: lumberjack
+    config:
+      filename: ` + filepath.Join(dir, "a.log")
+	}
+
+	writeFile(t, filename, config("info", "warn"))
+	w, err := NewConfigWatcher(filename, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	c := w.Config()
+	overrides := c.levelOverrides()
+	overrides.set(c, "", zap.DebugLevel, 100*time.Millisecond)
+	overrides.set(c, "db", zap.DebugLevel, 100*time.Millisecond)
+	overrides.set(c, "http", zap.DebugLevel, 100*time.Millisecond)
+
+	// the reloaded levels of root and db cancel their overrides, the override of http is kept.
+	writeFile(t, filename, config("warn", "error"))
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	c = w.Config()
+	expect := func(when string, root zapcore.Level, levels map[string]zapcore.Level) {
+		if level := c.Level.Level(); level != root {
+			t.Errorf("%s: root level should be %s, got %s", when, root, level)
+		}
+		for name, level := range levels {
+			if l, _ := c.Levels.Get(name); l != level {
+				t.Errorf("%s: %s level should be %s, got %s", when, name, level, l)
+			}
+		}
+	}
+
+	expect("reloaded", zap.WarnLevel, map[string]zapcore.Level{"db": zap.ErrorLevel, "http": zap.DebugLevel})
+	if c.levelOverrides().expireAt("db") != 0 || c.levelOverrides().expireAt("http") == 0 {
+		t.Error("override of db should be canceled and http should be kept")
+	}
+
+	// the expired override of http is reverted, the reloaded levels are not reverted.
+	time.Sleep(300 * time.Millisecond)
+	expect("expired", zap.WarnLevel, map[string]zapcore.Level{"db": zap.ErrorLevel, "http": zap.InfoLevel})
+}
+
+func TestSwapCore_InFlight(t *testing.T) {
+	old := &bytes.Buffer{}
+	core := newSwapCore(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(old), zap.InfoLevel))
+	logger := zap.New(core).With(zap.String("with", "field"))
+
+	// checked before swap and written after swap.
+	ce := logger.Check(zap.InfoLevel, "in-flight")
+	if ce == nil {
+		t.Fatal("entry should be enabled")
+	}
+	if logger.Check(zap.DebugLevel, "disabled") != nil {
+		t.Fatal("entry should be disabled")
+	}
+
+	state := core.swap(zapcore.NewNopCore())
+	if state.wait(10 * time.Millisecond) {
+		t.Fatal("old core should be held by the in-flight entry")
+	}
+
+	ce.Write()
+	if !state.wait(time.Second) {
+		t.Fatal("old core should be released after the entry written")
+	}
+	if !strings.Contains(old.String(), `"msg":"in-flight","with":"field"`) {
+		t.Errorf("in-flight entry should be written to old core: %s", old.String())
+	}
+
+	// new entries go to the new core.
+	logger.Info("after swap")
+	if strings.Contains(old.String(), "after swap") {
+		t.Errorf("entry should not be written to old core: %s", old.String())
+	}
+}
@@ -1,6 +1,10 @@
 package zap
 
-import "go.uber.org/zap"
+import (
+	"go.uber.org/zap"
+
+	"github.com/go-framework/zap/collector"
+)
 
 // Defined as zap Logger.
 type Logger = zap.Logger
@@ -8,6 +12,29 @@ type Logger = zap.Logger
 // Defined as zap Option.
 type Option = zap.Option
 
+// Defined as collector Level.
+type Level = collector.Level
+
+// Defined as collector Level values.
+const (
+	Level_Debug  = collector.Level_Debug
+	Level_Info   = collector.Level_Info
+	Level_Warn   = collector.Level_Warn
+	Level_Error  = collector.Level_Error
+	Level_DPanic = collector.Level_DPanic
+	Level_Panic  = collector.Level_Panic
+	Level_Fatal  = collector.Level_Fatal
+)
+
+// Defined as collector Level names and values.
+var (
+	Level_name  = collector.Level_name
+	Level_value = collector.Level_value
+)
+
+// Defined as collector LogEntry.
+type LogEntry = collector.LogEntry
+
 // new zap logger with config.
 func NewZapLogger(config *Config, opts ...zap.Option) *zap.Logger {
 	if config == nil {
@@ -15,3 +42,11 @@ func NewZapLogger(config *Config, opts ...zap.Option) *zap.Logger {
 	}
 	return config.NewZapLogger(opts...)
 }
+
+// Build zap logger with config, return error when config is invalid.
+func Build(config *Config, opts ...zap.Option) (*zap.Logger, error) {
+	if config == nil {
+		config = DefaultZapConfig
+	}
+	return config.Build(opts...)
+}
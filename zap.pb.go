@@ -6,6 +6,7 @@ package zap
 import (
 	"context"
 	"fmt"
+	"github.com/go-framework/zap/collector"
 	"github.com/golang/protobuf/proto"
 	"google.golang.org/grpc"
 	"math"
@@ -22,65 +23,12 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
-// Logger level enum.
-// Level should be reduce 1 than equal zap logger level value.
-type Level int32
-
-const (
-	// DebugLevel logs are typically voluminous, and are usually disabled in
-	// production.
-	Level_Debug Level = 0
-	// InfoLevel is the default logging priority.
-	Level_Info Level = 1
-	// WarnLevel logs are more important than Info, but don't need individual
-	// human review.
-	Level_Warn Level = 2
-	// ErrorLevel logs are high-priority. If an application is running smoothly,
-	// it shouldn't generate any error-level logs.
-	Level_Error Level = 3
-	// DPanicLevel logs are particularly important errors. In development the
-	// logger panics after writing the message.
-	Level_DPanic Level = 4
-	// PanicLevel logs a message, then panics.
-	Level_Panic Level = 5
-	// FatalLevel logs a message, then calls os.Exit(1).
-	Level_Fatal Level = 6
-)
-
-var Level_name = map[int32]string{
-	0: "Debug",
-	1: "Info",
-	2: "Warn",
-	3: "Error",
-	4: "DPanic",
-	5: "Panic",
-	6: "Fatal",
-}
-
-var Level_value = map[string]int32{
-	"Debug":  0,
-	"Info":   1,
-	"Warn":   2,
-	"Error":  3,
-	"DPanic": 4,
-	"Panic":  5,
-	"Fatal":  6,
-}
-
-func (x Level) String() string {
-	return proto.EnumName(Level_name, int32(x))
-}
-
-func (Level) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_500c6d736cd51ba2, []int{0}
-}
-
 // Atomic level message.
 type AtomicLevel struct {
-	Level                Level    `protobuf:"varint,1,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Level                collector.Level `protobuf:"varint,1,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *AtomicLevel) Reset()         { *m = AtomicLevel{} }
@@ -108,11 +56,11 @@ func (m *AtomicLevel) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_AtomicLevel proto.InternalMessageInfo
 
-func (m *AtomicLevel) GetLevel() Level {
+func (m *AtomicLevel) GetLevel() collector.Level {
 	if m != nil {
 		return m.Level
 	}
-	return Level_Debug
+	return collector.Level_Debug
 }
 
 // Empty message.
@@ -147,29 +95,544 @@ func (m *Empty) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_Empty proto.InternalMessageInfo
 
+// Logger name message.
+type LoggerName struct {
+	// Logger name prefix, empty name is the root logger.
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoggerName) Reset()         { *m = LoggerName{} }
+func (m *LoggerName) String() string { return proto.CompactTextString(m) }
+func (*LoggerName) ProtoMessage()    {}
+func (*LoggerName) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{2}
+}
+
+func (m *LoggerName) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoggerName.Unmarshal(m, b)
+}
+func (m *LoggerName) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoggerName.Marshal(b, m, deterministic)
+}
+func (m *LoggerName) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoggerName.Merge(m, src)
+}
+func (m *LoggerName) XXX_Size() int {
+	return xxx_messageInfo_LoggerName.Size(m)
+}
+func (m *LoggerName) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoggerName.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoggerName proto.InternalMessageInfo
+
+func (m *LoggerName) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// Logger level message.
+type LoggerLevel struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Logger level.
+	Level collector.Level `protobuf:"varint,2,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	// Override expire time as unix seconds, zero is never expire.
+	ExpireAt             int64    `protobuf:"varint,3,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoggerLevel) Reset()         { *m = LoggerLevel{} }
+func (m *LoggerLevel) String() string { return proto.CompactTextString(m) }
+func (*LoggerLevel) ProtoMessage()    {}
+func (*LoggerLevel) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{3}
+}
+
+func (m *LoggerLevel) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoggerLevel.Unmarshal(m, b)
+}
+func (m *LoggerLevel) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoggerLevel.Marshal(b, m, deterministic)
+}
+func (m *LoggerLevel) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoggerLevel.Merge(m, src)
+}
+func (m *LoggerLevel) XXX_Size() int {
+	return xxx_messageInfo_LoggerLevel.Size(m)
+}
+func (m *LoggerLevel) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoggerLevel.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoggerLevel proto.InternalMessageInfo
+
+func (m *LoggerLevel) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LoggerLevel) GetLevel() collector.Level {
+	if m != nil {
+		return m.Level
+	}
+	return collector.Level_Debug
+}
+
+func (m *LoggerLevel) GetExpireAt() int64 {
+	if m != nil {
+		return m.ExpireAt
+	}
+	return 0
+}
+
+// Loggers message.
+type Loggers struct {
+	// Logger levels, the root logger is the first.
+	Loggers              []*LoggerLevel `protobuf:"bytes,1,rep,name=loggers,proto3" json:"loggers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Loggers) Reset()         { *m = Loggers{} }
+func (m *Loggers) String() string { return proto.CompactTextString(m) }
+func (*Loggers) ProtoMessage()    {}
+func (*Loggers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{4}
+}
+
+func (m *Loggers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Loggers.Unmarshal(m, b)
+}
+func (m *Loggers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Loggers.Marshal(b, m, deterministic)
+}
+func (m *Loggers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Loggers.Merge(m, src)
+}
+func (m *Loggers) XXX_Size() int {
+	return xxx_messageInfo_Loggers.Size(m)
+}
+func (m *Loggers) XXX_DiscardUnknown() {
+	xxx_messageInfo_Loggers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Loggers proto.InternalMessageInfo
+
+func (m *Loggers) GetLoggers() []*LoggerLevel {
+	if m != nil {
+		return m.Loggers
+	}
+	return nil
+}
+
+// Set logger level request message.
+type SetLoggerLevelRequest struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Logger level.
+	Level collector.Level `protobuf:"varint,2,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	// Override time to live in seconds, the level is reverted when expired, zero is permanent.
+	TtlSeconds           int64    `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SetLoggerLevelRequest) Reset()         { *m = SetLoggerLevelRequest{} }
+func (m *SetLoggerLevelRequest) String() string { return proto.CompactTextString(m) }
+func (*SetLoggerLevelRequest) ProtoMessage()    {}
+func (*SetLoggerLevelRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{5}
+}
+
+func (m *SetLoggerLevelRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SetLoggerLevelRequest.Unmarshal(m, b)
+}
+func (m *SetLoggerLevelRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SetLoggerLevelRequest.Marshal(b, m, deterministic)
+}
+func (m *SetLoggerLevelRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SetLoggerLevelRequest.Merge(m, src)
+}
+func (m *SetLoggerLevelRequest) XXX_Size() int {
+	return xxx_messageInfo_SetLoggerLevelRequest.Size(m)
+}
+func (m *SetLoggerLevelRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SetLoggerLevelRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SetLoggerLevelRequest proto.InternalMessageInfo
+
+func (m *SetLoggerLevelRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SetLoggerLevelRequest) GetLevel() collector.Level {
+	if m != nil {
+		return m.Level
+	}
+	return collector.Level_Debug
+}
+
+func (m *SetLoggerLevelRequest) GetTtlSeconds() int64 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
+// Level change message.
+type LevelChange struct {
+	// Logger name prefix, empty name is the root logger.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Effective level before change.
+	Previous collector.Level `protobuf:"varint,2,opt,name=previous,proto3,enum=zap.Level" json:"previous,omitempty"`
+	// Effective level after change.
+	Level collector.Level `protobuf:"varint,3,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	// Override expire time as unix seconds, zero is never expire.
+	ExpireAt             int64    `protobuf:"varint,4,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LevelChange) Reset()         { *m = LevelChange{} }
+func (m *LevelChange) String() string { return proto.CompactTextString(m) }
+func (*LevelChange) ProtoMessage()    {}
+func (*LevelChange) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{6}
+}
+
+func (m *LevelChange) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LevelChange.Unmarshal(m, b)
+}
+func (m *LevelChange) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LevelChange.Marshal(b, m, deterministic)
+}
+func (m *LevelChange) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LevelChange.Merge(m, src)
+}
+func (m *LevelChange) XXX_Size() int {
+	return xxx_messageInfo_LevelChange.Size(m)
+}
+func (m *LevelChange) XXX_DiscardUnknown() {
+	xxx_messageInfo_LevelChange.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LevelChange proto.InternalMessageInfo
+
+func (m *LevelChange) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LevelChange) GetPrevious() collector.Level {
+	if m != nil {
+		return m.Previous
+	}
+	return collector.Level_Debug
+}
+
+func (m *LevelChange) GetLevel() collector.Level {
+	if m != nil {
+		return m.Level
+	}
+	return collector.Level_Debug
+}
+
+func (m *LevelChange) GetExpireAt() int64 {
+	if m != nil {
+		return m.ExpireAt
+	}
+	return 0
+}
+
+// Writer state message.
+type WriterState struct {
+	// Write index.
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Write name.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Write minimum enabled level, valid when has_level is true.
+	Level    collector.Level `protobuf:"varint,3,opt,name=level,proto3,enum=zap.Level" json:"level,omitempty"`
+	HasLevel bool            `protobuf:"varint,4,opt,name=has_level,json=hasLevel,proto3" json:"has_level,omitempty"`
+	// Write maximum enabled level, valid when has_max_level is true.
+	MaxLevel    collector.Level `protobuf:"varint,5,opt,name=max_level,json=maxLevel,proto3,enum=zap.Level" json:"max_level,omitempty"`
+	HasMaxLevel bool            `protobuf:"varint,6,opt,name=has_max_level,json=hasMaxLevel,proto3" json:"has_max_level,omitempty"`
+	// Write encoding.
+	Encoding string `protobuf:"bytes,7,opt,name=encoding,proto3" json:"encoding,omitempty"`
+	// Write is healthy.
+	Healthy bool `protobuf:"varint,8,opt,name=healthy,proto3" json:"healthy,omitempty"`
+	// Health error.
+	Error                string   `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WriterState) Reset()         { *m = WriterState{} }
+func (m *WriterState) String() string { return proto.CompactTextString(m) }
+func (*WriterState) ProtoMessage()    {}
+func (*WriterState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{7}
+}
+
+func (m *WriterState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WriterState.Unmarshal(m, b)
+}
+func (m *WriterState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WriterState.Marshal(b, m, deterministic)
+}
+func (m *WriterState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WriterState.Merge(m, src)
+}
+func (m *WriterState) XXX_Size() int {
+	return xxx_messageInfo_WriterState.Size(m)
+}
+func (m *WriterState) XXX_DiscardUnknown() {
+	xxx_messageInfo_WriterState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WriterState proto.InternalMessageInfo
+
+func (m *WriterState) GetIndex() int32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *WriterState) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WriterState) GetLevel() collector.Level {
+	if m != nil {
+		return m.Level
+	}
+	return collector.Level_Debug
+}
+
+func (m *WriterState) GetHasLevel() bool {
+	if m != nil {
+		return m.HasLevel
+	}
+	return false
+}
+
+func (m *WriterState) GetMaxLevel() collector.Level {
+	if m != nil {
+		return m.MaxLevel
+	}
+	return collector.Level_Debug
+}
+
+func (m *WriterState) GetHasMaxLevel() bool {
+	if m != nil {
+		return m.HasMaxLevel
+	}
+	return false
+}
+
+func (m *WriterState) GetEncoding() string {
+	if m != nil {
+		return m.Encoding
+	}
+	return ""
+}
+
+func (m *WriterState) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *WriterState) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// Writers message.
+type Writers struct {
+	// Writer states in config order.
+	Writers              []*WriterState `protobuf:"bytes,1,rep,name=writers,proto3" json:"writers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *Writers) Reset()         { *m = Writers{} }
+func (m *Writers) String() string { return proto.CompactTextString(m) }
+func (*Writers) ProtoMessage()    {}
+func (*Writers) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{8}
+}
+
+func (m *Writers) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Writers.Unmarshal(m, b)
+}
+func (m *Writers) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Writers.Marshal(b, m, deterministic)
+}
+func (m *Writers) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Writers.Merge(m, src)
+}
+func (m *Writers) XXX_Size() int {
+	return xxx_messageInfo_Writers.Size(m)
+}
+func (m *Writers) XXX_DiscardUnknown() {
+	xxx_messageInfo_Writers.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Writers proto.InternalMessageInfo
+
+func (m *Writers) GetWriters() []*WriterState {
+	if m != nil {
+		return m.Writers
+	}
+	return nil
+}
+
+// Tail request message.
+type TailRequest struct {
+	// Minimum entry level.
+	MinLevel collector.Level `protobuf:"varint,1,opt,name=min_level,json=minLevel,proto3,enum=zap.Level" json:"min_level,omitempty"`
+	// Logger name prefix, matched by name segments, empty matches all loggers.
+	Logger string `protobuf:"bytes,2,opt,name=logger,proto3" json:"logger,omitempty"`
+	// Fields should be equal, values are compared as LogEntry fields.
+	Fields               map[string]string `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TailRequest) Reset()         { *m = TailRequest{} }
+func (m *TailRequest) String() string { return proto.CompactTextString(m) }
+func (*TailRequest) ProtoMessage()    {}
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_500c6d736cd51ba2, []int{9}
+}
+
+func (m *TailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailRequest.Unmarshal(m, b)
+}
+func (m *TailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailRequest.Marshal(b, m, deterministic)
+}
+func (m *TailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailRequest.Merge(m, src)
+}
+func (m *TailRequest) XXX_Size() int {
+	return xxx_messageInfo_TailRequest.Size(m)
+}
+func (m *TailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailRequest proto.InternalMessageInfo
+
+func (m *TailRequest) GetMinLevel() collector.Level {
+	if m != nil {
+		return m.MinLevel
+	}
+	return collector.Level_Debug
+}
+
+func (m *TailRequest) GetLogger() string {
+	if m != nil {
+		return m.Logger
+	}
+	return ""
+}
+
+func (m *TailRequest) GetFields() map[string]string {
+	if m != nil {
+		return m.Fields
+	}
+	return nil
+}
+
 func init() {
-	proto.RegisterEnum("zap.Level", Level_name, Level_value)
 	proto.RegisterType((*AtomicLevel)(nil), "zap.AtomicLevel")
 	proto.RegisterType((*Empty)(nil), "zap.Empty")
+	proto.RegisterType((*LoggerName)(nil), "zap.LoggerName")
+	proto.RegisterType((*LoggerLevel)(nil), "zap.LoggerLevel")
+	proto.RegisterType((*Loggers)(nil), "zap.Loggers")
+	proto.RegisterType((*SetLoggerLevelRequest)(nil), "zap.SetLoggerLevelRequest")
+	proto.RegisterType((*LevelChange)(nil), "zap.LevelChange")
+	proto.RegisterType((*WriterState)(nil), "zap.WriterState")
+	proto.RegisterType((*Writers)(nil), "zap.Writers")
+	proto.RegisterType((*TailRequest)(nil), "zap.TailRequest")
+	proto.RegisterMapType((map[string]string)(nil), "zap.TailRequest.FieldsEntry")
 }
 
 func init() { proto.RegisterFile("zap.proto", fileDescriptor_500c6d736cd51ba2) }
 
 var fileDescriptor_500c6d736cd51ba2 = []byte{
-	// 203 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x8f, 0xc1, 0xeb, 0x82, 0x30,
-	0x14, 0xc7, 0x7f, 0xfe, 0x74, 0xa6, 0xaf, 0x88, 0xb1, 0x53, 0x78, 0x12, 0x4f, 0xd2, 0xc1, 0xc0,
-	0xfe, 0x82, 0x40, 0x8b, 0xa0, 0x43, 0xe4, 0xa1, 0xf3, 0x94, 0x15, 0x82, 0xba, 0x31, 0x96, 0x90,
-	0x7f, 0x7d, 0x6c, 0x23, 0x10, 0xba, 0x7d, 0xd8, 0xe7, 0xbd, 0x7d, 0xbf, 0x0f, 0xc2, 0x89, 0x8a,
-	0x4c, 0x48, 0xae, 0x38, 0x71, 0x27, 0x2a, 0x92, 0x1d, 0x2c, 0x0f, 0x8a, 0xf7, 0x6d, 0x73, 0x61,
-	0x23, 0xeb, 0x48, 0x0c, 0xa8, 0xd3, 0xb0, 0x71, 0x62, 0x27, 0x5d, 0xe7, 0x90, 0xe9, 0x71, 0xa3,
-	0x6e, 0x56, 0x24, 0x0b, 0x40, 0x65, 0x2f, 0xd4, 0x7b, 0x5b, 0x01, 0xb2, 0x3b, 0x21, 0xa0, 0x82,
-	0xd5, 0xaf, 0x27, 0xfe, 0x23, 0x01, 0x78, 0xe7, 0xe1, 0xc1, 0xb1, 0xa3, 0xe9, 0x4e, 0xe5, 0x80,
-	0xff, 0xb5, 0x2e, 0xa5, 0xe4, 0x12, 0xbb, 0x04, 0xc0, 0x2f, 0xae, 0x74, 0x68, 0x1b, 0xec, 0xe9,
-	0x67, 0x8b, 0x48, 0xe3, 0x91, 0x2a, 0xda, 0x61, 0x3f, 0xaf, 0x61, 0x65, 0x3e, 0xad, 0x98, 0x1c,
-	0xdb, 0x86, 0x91, 0x14, 0x82, 0x13, 0x53, 0x36, 0xc7, 0x96, 0x31, 0xe1, 0x11, 0x36, 0x3c, 0x6f,
-	0x9e, 0x42, 0x50, 0x7d, 0x27, 0x7f, 0x6c, 0x34, 0xdb, 0xad, 0x7d, 0x73, 0xfe, 0xfe, 0x13, 0x00,
-	0x00, 0xff, 0xff, 0x1f, 0xeb, 0x3d, 0xe7, 0x0b, 0x01, 0x00, 0x00,
+	// 644 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0xcd, 0x6e, 0xd3, 0x40,
+	0x10, 0x96, 0xe3, 0x26, 0x71, 0xc6, 0x6d, 0xa9, 0x56, 0x80, 0x8c, 0x41, 0x22, 0xf2, 0x81, 0x06,
+	0x24, 0x1a, 0x14, 0x40, 0x14, 0x0e, 0x48, 0x15, 0x2a, 0x5c, 0x0a, 0x07, 0x87, 0x9f, 0x63, 0x58,
+	0xdc, 0x21, 0x5e, 0xe1, 0x3f, 0xd6, 0xdb, 0x90, 0xf4, 0x09, 0xb8, 0xf1, 0x48, 0xbc, 0x18, 0x07,
+	0xb4, 0xbb, 0x76, 0xb2, 0xc5, 0x69, 0x15, 0x71, 0xdb, 0xf1, 0x7c, 0x33, 0xf3, 0xcd, 0xec, 0x37,
+	0x6b, 0xe8, 0x9d, 0xd3, 0xe2, 0xa0, 0xe0, 0xb9, 0xc8, 0x89, 0x7d, 0x4e, 0x0b, 0xff, 0x56, 0x94,
+	0x27, 0x09, 0x46, 0x22, 0xe7, 0xc3, 0xe5, 0x49, 0xfb, 0x83, 0x21, 0xb8, 0x47, 0x22, 0x4f, 0x59,
+	0x74, 0x82, 0x33, 0x4c, 0x48, 0x1f, 0xda, 0x89, 0x3c, 0x78, 0x56, 0xdf, 0x1a, 0xec, 0x8e, 0xe0,
+	0x40, 0x66, 0x52, 0xae, 0x50, 0x3b, 0x82, 0x2e, 0xb4, 0x8f, 0xd3, 0x42, 0x2c, 0x82, 0x3e, 0xc0,
+	0x49, 0x3e, 0x9d, 0x22, 0x7f, 0x47, 0x53, 0x24, 0x04, 0xb6, 0x32, 0x9a, 0xa2, 0x8a, 0xeb, 0x85,
+	0xea, 0x1c, 0x7c, 0x06, 0x57, 0x23, 0x74, 0xee, 0x35, 0x90, 0x55, 0xbd, 0xd6, 0x25, 0xf5, 0xc8,
+	0x6d, 0xe8, 0xe1, 0xbc, 0x60, 0x1c, 0x27, 0x54, 0x78, 0x76, 0xdf, 0x1a, 0xd8, 0xa1, 0xa3, 0x3f,
+	0x1c, 0x89, 0xe0, 0x29, 0x74, 0x75, 0x85, 0x92, 0x3c, 0x80, 0x6e, 0xa2, 0x8f, 0x9e, 0xd5, 0xb7,
+	0x07, 0xee, 0x68, 0x4f, 0xe7, 0x5a, 0x11, 0x08, 0x6b, 0x40, 0x90, 0xc1, 0x8d, 0x31, 0x0a, 0xd3,
+	0x85, 0xdf, 0xcf, 0xb0, 0x14, 0xff, 0x49, 0xf1, 0x2e, 0xb8, 0x42, 0x24, 0x93, 0x12, 0xa3, 0x3c,
+	0x3b, 0x2d, 0x2b, 0x92, 0x20, 0x44, 0x32, 0xd6, 0x5f, 0x82, 0x9f, 0x16, 0xb8, 0x2a, 0xe2, 0x55,
+	0x4c, 0xb3, 0xe9, 0xda, 0x61, 0x91, 0x7b, 0xe0, 0x14, 0x1c, 0x67, 0x2c, 0x3f, 0x2b, 0xd7, 0x54,
+	0x5a, 0xfa, 0x56, 0x74, 0xec, 0x8d, 0x26, 0xb6, 0xf5, 0xcf, 0xc4, 0x7e, 0xb5, 0xc0, 0xfd, 0xc4,
+	0x99, 0x40, 0x3e, 0x16, 0x54, 0x20, 0xb9, 0x0e, 0x6d, 0x96, 0x9d, 0xe2, 0x5c, 0x71, 0x69, 0x87,
+	0xda, 0x58, 0x12, 0x6c, 0xad, 0x9b, 0xc3, 0x55, 0x85, 0x63, 0x5a, 0x4e, 0x34, 0x4a, 0x16, 0x76,
+	0x42, 0x27, 0xa6, 0xa5, 0xbe, 0xfd, 0x7d, 0xe8, 0xa5, 0x74, 0x5e, 0x39, 0xdb, 0xcd, 0x06, 0x53,
+	0x3a, 0xd7, 0xc0, 0x00, 0x76, 0x64, 0x96, 0x15, 0xb8, 0xa3, 0x32, 0xb9, 0x31, 0x2d, 0xdf, 0xd6,
+	0x18, 0x1f, 0x1c, 0xcc, 0xa2, 0xfc, 0x94, 0x65, 0x53, 0xaf, 0xab, 0x38, 0x2e, 0x6d, 0xe2, 0x41,
+	0x37, 0x46, 0x9a, 0x88, 0x78, 0xe1, 0x39, 0x2a, 0xb2, 0x36, 0x65, 0xaf, 0xc8, 0x79, 0xce, 0xbd,
+	0x9e, 0x0a, 0xd1, 0x86, 0xd4, 0x90, 0x1e, 0x88, 0xd2, 0xd0, 0x0f, 0x7d, 0xbc, 0xa0, 0x21, 0x63,
+	0x5e, 0x61, 0x0d, 0x08, 0x7e, 0x5b, 0xe0, 0xbe, 0xa7, 0x6c, 0x29, 0x1d, 0xd9, 0x1f, 0xcb, 0x26,
+	0x97, 0x6d, 0x8f, 0x93, 0xb2, 0x4c, 0x73, 0xbf, 0x09, 0x1d, 0xad, 0xc3, 0x6a, 0xba, 0x95, 0x45,
+	0x9e, 0x40, 0xe7, 0x2b, 0xc3, 0x44, 0x09, 0x48, 0xd6, 0xbe, 0xa3, 0xa2, 0x8d, 0x12, 0x07, 0xaf,
+	0x95, 0xfb, 0x38, 0x13, 0x7c, 0x11, 0x56, 0x58, 0xff, 0x39, 0xb8, 0xc6, 0x67, 0xb2, 0x07, 0xf6,
+	0x37, 0x5c, 0x54, 0xc2, 0x92, 0x47, 0xd9, 0xf4, 0x8c, 0x26, 0x67, 0xf5, 0x5d, 0x6a, 0xe3, 0x45,
+	0xeb, 0xd0, 0x1a, 0xfd, 0xb1, 0x60, 0x5b, 0x51, 0x1a, 0x23, 0x9f, 0xb1, 0x08, 0xc9, 0x00, 0x9c,
+	0x37, 0x28, 0x34, 0x4b, 0xcd, 0x5d, 0x6d, 0xba, 0xaf, 0xa7, 0x60, 0x3e, 0x13, 0x03, 0x70, 0xc6,
+	0x35, 0xb2, 0xe1, 0xf5, 0x8d, 0x58, 0xb2, 0x0f, 0xee, 0x09, 0x2b, 0x45, 0xbd, 0xa5, 0x66, 0xda,
+	0x6d, 0x63, 0x41, 0x4b, 0xf2, 0x12, 0x76, 0x2f, 0xee, 0x24, 0xf1, 0x95, 0x7f, 0xed, 0xa2, 0xfa,
+	0x8d, 0xe5, 0x26, 0x43, 0xd8, 0xfb, 0x90, 0x95, 0x17, 0x33, 0x5c, 0x33, 0x50, 0xf2, 0x95, 0x32,
+	0x99, 0xc9, 0xf6, 0x77, 0xcd, 0xf6, 0x3f, 0x8e, 0xc8, 0x43, 0x63, 0x00, 0x8d, 0xd8, 0x66, 0xc9,
+	0x43, 0x63, 0x0a, 0x1b, 0x90, 0x35, 0x1e, 0x80, 0x21, 0x80, 0x26, 0x7b, 0x65, 0x29, 0x23, 0x60,
+	0xe3, 0x31, 0x56, 0xc0, 0x5a, 0xd1, 0x4d, 0x60, 0xe5, 0x19, 0x3d, 0x53, 0xcf, 0x77, 0x7d, 0xf5,
+	0xf7, 0x61, 0x4b, 0x2a, 0xad, 0xba, 0x4c, 0x43, 0x74, 0xfe, 0x4e, 0x9d, 0x5e, 0x09, 0xec, 0x91,
+	0xf5, 0xa5, 0xa3, 0x7e, 0x1c, 0x8f, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0xbf, 0x9a, 0x55, 0xb0,
+	0x65, 0x06, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -188,6 +651,12 @@ type LevelServiceClient interface {
 	GetLevel(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*AtomicLevel, error)
 	// Set logger atomic Level.
 	SetLevel(ctx context.Context, in *AtomicLevel, opts ...grpc.CallOption) (*Empty, error)
+	// List the root logger and named loggers levels.
+	ListLoggers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Loggers, error)
+	// Set logger level by name, optionally reverted after ttl.
+	SetLoggerLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*LoggerLevel, error)
+	// Unset named logger level, the logger inherits the parent level.
+	UnsetLoggerLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type levelServiceClient struct {
@@ -216,12 +685,45 @@ func (c *levelServiceClient) SetLevel(ctx context.Context, in *AtomicLevel, opts
 	return out, nil
 }
 
+func (c *levelServiceClient) ListLoggers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Loggers, error) {
+	out := new(Loggers)
+	err := c.cc.Invoke(ctx, "/zap.LevelService/ListLoggers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceClient) SetLoggerLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*LoggerLevel, error) {
+	out := new(LoggerLevel)
+	err := c.cc.Invoke(ctx, "/zap.LevelService/SetLoggerLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceClient) UnsetLoggerLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/zap.LevelService/UnsetLoggerLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LevelServiceServer is the server API for LevelService service.
 type LevelServiceServer interface {
 	// Get logger atomic Level.
 	GetLevel(context.Context, *Empty) (*AtomicLevel, error)
 	// Set logger atomic Level.
 	SetLevel(context.Context, *AtomicLevel) (*Empty, error)
+	// List the root logger and named loggers levels.
+	ListLoggers(context.Context, *Empty) (*Loggers, error)
+	// Set logger level by name, optionally reverted after ttl.
+	SetLoggerLevel(context.Context, *SetLoggerLevelRequest) (*LoggerLevel, error)
+	// Unset named logger level, the logger inherits the parent level.
+	UnsetLoggerLevel(context.Context, *LoggerName) (*Empty, error)
 }
 
 func RegisterLevelServiceServer(s *grpc.Server, srv LevelServiceServer) {
@@ -264,6 +766,60 @@ func _LevelService_SetLevel_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LevelService_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelService/ListLoggers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).ListLoggers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelService_SetLoggerLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLoggerLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).SetLoggerLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelService/SetLoggerLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).SetLoggerLevel(ctx, req.(*SetLoggerLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelService_UnsetLoggerLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoggerName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceServer).UnsetLoggerLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelService/UnsetLoggerLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceServer).UnsetLoggerLevel(ctx, req.(*LoggerName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _LevelService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "zap.LevelService",
 	HandlerType: (*LevelServiceServer)(nil),
@@ -276,7 +832,318 @@ var _LevelService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "SetLevel",
 			Handler:    _LevelService_SetLevel_Handler,
 		},
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LevelService_ListLoggers_Handler,
+		},
+		{
+			MethodName: "SetLoggerLevel",
+			Handler:    _LevelService_SetLoggerLevel_Handler,
+		},
+		{
+			MethodName: "UnsetLoggerLevel",
+			Handler:    _LevelService_UnsetLoggerLevel_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "zap.proto",
 }
+
+// LevelServiceV2Client is the client API for LevelServiceV2 service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LevelServiceV2Client interface {
+	// Get effective level of logger name, empty name is the root logger.
+	GetLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*LoggerLevel, error)
+	// Set logger level by name, optionally reverted after ttl.
+	SetLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*LevelChange, error)
+	// Unset named logger level, the logger inherits the parent level.
+	UnsetLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*LevelChange, error)
+	// List the root logger and named loggers levels.
+	ListLoggers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Loggers, error)
+	// List writers with their health.
+	ListWriters(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Writers, error)
+}
+
+type levelServiceV2Client struct {
+	cc *grpc.ClientConn
+}
+
+func NewLevelServiceV2Client(cc *grpc.ClientConn) LevelServiceV2Client {
+	return &levelServiceV2Client{cc}
+}
+
+func (c *levelServiceV2Client) GetLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*LoggerLevel, error) {
+	out := new(LoggerLevel)
+	err := c.cc.Invoke(ctx, "/zap.LevelServiceV2/GetLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceV2Client) SetLevel(ctx context.Context, in *SetLoggerLevelRequest, opts ...grpc.CallOption) (*LevelChange, error) {
+	out := new(LevelChange)
+	err := c.cc.Invoke(ctx, "/zap.LevelServiceV2/SetLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceV2Client) UnsetLevel(ctx context.Context, in *LoggerName, opts ...grpc.CallOption) (*LevelChange, error) {
+	out := new(LevelChange)
+	err := c.cc.Invoke(ctx, "/zap.LevelServiceV2/UnsetLevel", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceV2Client) ListLoggers(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Loggers, error) {
+	out := new(Loggers)
+	err := c.cc.Invoke(ctx, "/zap.LevelServiceV2/ListLoggers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *levelServiceV2Client) ListWriters(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Writers, error) {
+	out := new(Writers)
+	err := c.cc.Invoke(ctx, "/zap.LevelServiceV2/ListWriters", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// LevelServiceV2Server is the server API for LevelServiceV2 service.
+type LevelServiceV2Server interface {
+	// Get effective level of logger name, empty name is the root logger.
+	GetLevel(context.Context, *LoggerName) (*LoggerLevel, error)
+	// Set logger level by name, optionally reverted after ttl.
+	SetLevel(context.Context, *SetLoggerLevelRequest) (*LevelChange, error)
+	// Unset named logger level, the logger inherits the parent level.
+	UnsetLevel(context.Context, *LoggerName) (*LevelChange, error)
+	// List the root logger and named loggers levels.
+	ListLoggers(context.Context, *Empty) (*Loggers, error)
+	// List writers with their health.
+	ListWriters(context.Context, *Empty) (*Writers, error)
+}
+
+func RegisterLevelServiceV2Server(s *grpc.Server, srv LevelServiceV2Server) {
+	s.RegisterService(&_LevelServiceV2_serviceDesc, srv)
+}
+
+func _LevelServiceV2_GetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoggerName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceV2Server).GetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelServiceV2/GetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceV2Server).GetLevel(ctx, req.(*LoggerName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelServiceV2_SetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetLoggerLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceV2Server).SetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelServiceV2/SetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceV2Server).SetLevel(ctx, req.(*SetLoggerLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelServiceV2_UnsetLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoggerName)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceV2Server).UnsetLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelServiceV2/UnsetLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceV2Server).UnsetLevel(ctx, req.(*LoggerName))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelServiceV2_ListLoggers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceV2Server).ListLoggers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelServiceV2/ListLoggers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceV2Server).ListLoggers(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LevelServiceV2_ListWriters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LevelServiceV2Server).ListWriters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/zap.LevelServiceV2/ListWriters",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LevelServiceV2Server).ListWriters(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _LevelServiceV2_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "zap.LevelServiceV2",
+	HandlerType: (*LevelServiceV2Server)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevel",
+			Handler:    _LevelServiceV2_GetLevel_Handler,
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    _LevelServiceV2_SetLevel_Handler,
+		},
+		{
+			MethodName: "UnsetLevel",
+			Handler:    _LevelServiceV2_UnsetLevel_Handler,
+		},
+		{
+			MethodName: "ListLoggers",
+			Handler:    _LevelServiceV2_ListLoggers_Handler,
+		},
+		{
+			MethodName: "ListWriters",
+			Handler:    _LevelServiceV2_ListWriters_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "zap.proto",
+}
+
+// LogServiceClient is the client API for LogService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type LogServiceClient interface {
+	// Tail the live log entries of logger, entries are dropped when the client is too slow.
+	Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogService_TailClient, error)
+}
+
+type logServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLogServiceClient(cc *grpc.ClientConn) LogServiceClient {
+	return &logServiceClient{cc}
+}
+
+func (c *logServiceClient) Tail(ctx context.Context, in *TailRequest, opts ...grpc.CallOption) (LogService_TailClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_LogService_serviceDesc.Streams[0], "/zap.LogService/Tail", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &logServiceTailClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type LogService_TailClient interface {
+	Recv() (*collector.LogEntry, error)
+	grpc.ClientStream
+}
+
+type logServiceTailClient struct {
+	grpc.ClientStream
+}
+
+func (x *logServiceTailClient) Recv() (*collector.LogEntry, error) {
+	m := new(collector.LogEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// LogServiceServer is the server API for LogService service.
+type LogServiceServer interface {
+	// Tail the live log entries of logger, entries are dropped when the client is too slow.
+	Tail(*TailRequest, LogService_TailServer) error
+}
+
+func RegisterLogServiceServer(s *grpc.Server, srv LogServiceServer) {
+	s.RegisterService(&_LogService_serviceDesc, srv)
+}
+
+func _LogService_Tail_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogServiceServer).Tail(m, &logServiceTailServer{stream})
+}
+
+type LogService_TailServer interface {
+	Send(*collector.LogEntry) error
+	grpc.ServerStream
+}
+
+type logServiceTailServer struct {
+	grpc.ServerStream
+}
+
+func (x *logServiceTailServer) Send(m *collector.LogEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _LogService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "zap.LogService",
+	HandlerType: (*LogServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Tail",
+			Handler:       _LogService_Tail_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "zap.proto",
+}